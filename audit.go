@@ -0,0 +1,85 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// An AuditDevice describes a device which is logging
+// every request and response made to vault.
+//
+// More information about audit devices can be found here:
+// https://www.vaultproject.io/docs/audit/index.html
+type AuditDevice struct {
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Path        string            `json:"path"`
+	Local       bool              `json:"local"`
+	Options     map[string]string `json:"options"`
+}
+
+// AuditDevices contains information about the audit devices currently
+// enabled in vault, keyed by the path at which each is enabled.
+type AuditDevices map[string]AuditDevice
+
+type auditDevicesWrapper struct {
+	Data AuditDevices `json:"data"`
+}
+
+func (c *client) ListAudit() (AuditDevices, error) {
+	var wrapper auditDevicesWrapper
+	if err := c.get("/v1/sys/audit", &wrapper); err != nil {
+		return nil, errors.Wrap(err, "failed to list audit devices")
+	}
+	return wrapper.Data, nil
+}
+
+func (c *client) EnableAudit(path, auditType string, options map[string]string) error {
+	bs, err := json.Marshal(struct {
+		Type    string            `json:"type"`
+		Options map[string]string `json:"options,omitempty"`
+	}{Type: auditType, Options: options})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for enabling audit %q", path)
+	}
+
+	if err := c.post("/v1/sys/audit/"+strings.Trim(path, "/"), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to enable audit %q at %q", auditType, path)
+	}
+
+	return nil
+}
+
+func (c *client) DisableAudit(path string) error {
+	if err := c.delete("/v1/sys/audit/" + strings.Trim(path, "/")); err != nil {
+		return errors.Wrapf(err, "failed to disable audit at %q", path)
+	}
+	return nil
+}
+
+type auditHashWrapper struct {
+	Data struct {
+		Hash string `json:"hash"`
+	} `json:"data"`
+}
+
+// AuditHash returns the HMAC of input as it would appear in the logs of the
+// audit device at path, which can be used to search the logs for a value.
+func (c *client) AuditHash(path, input string) (string, error) {
+	bs, err := json.Marshal(struct {
+		Input string `json:"input"`
+	}{Input: input})
+	if err != nil {
+		return "", err
+	}
+
+	var wrapper auditHashWrapper
+	if err := c.post("/v1/sys/audit-hash/"+strings.Trim(path, "/"), string(bs), &wrapper); err != nil {
+		// do not provide input anywhere
+		return "", errors.Wrapf(err, "failed to hash with audit device at %q", path)
+	}
+
+	return wrapper.Data.Hash, nil
+}
@@ -0,0 +1,44 @@
+package vaultapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Audit(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	devices, err := client.ListAudit()
+	require.NoError(t, err)
+	_, exists := devices["file-test/"]
+	require.False(t, exists)
+
+	err = client.EnableAudit("file-test", "file", map[string]string{
+		"file_path": "/tmp/vault-audit-test.log",
+	})
+	require.NoError(t, err)
+
+	devices, err = client.ListAudit()
+	require.NoError(t, err)
+	require.Equal(t, "file", devices["file-test/"].Type)
+	require.Equal(t, "/tmp/vault-audit-test.log", devices["file-test/"].Options["file_path"])
+
+	hash, err := client.AuditHash("file-test", "my-secret-value")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hash, "hmac-sha256:"))
+
+	// the same input always produces the same hash
+	again, err := client.AuditHash("file-test/", "my-secret-value")
+	require.NoError(t, err)
+	require.Equal(t, hash, again)
+
+	err = client.DisableAudit("file-test")
+	require.NoError(t, err)
+
+	devices, err = client.ListAudit()
+	require.NoError(t, err)
+	_, exists = devices["file-test/"]
+	require.False(t, exists)
+}
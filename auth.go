@@ -29,8 +29,9 @@ type Auth interface {
 	RenewToken(id string, increment time.Duration) (RenewedToken, error)
 	RenewSelfToken(increment time.Duration) (RenewedToken, error)
 	ListTokenRoles() ([]string, error)
-	CreateTokenRole(data TokenRoleOptions) error
-	LookupTokenRole(name string) (LookedUpTokenRole, error)
+	CreateTokenRole(role TokenRole) error
+	LookupTokenRole(name string) (TokenRole, error)
+	UpdateTokenRole(name string, update func(*TokenRole)) error
 	DeleteTokenRole(name string) error
 }
 
@@ -200,26 +201,36 @@ func (c *client) ListTokenRoles() ([]string, error) {
 	return rolesWrapper.Data.Keys, nil
 }
 
-type TokenRoleOptions struct {
-	Name               string   `json:"role_name"`
-	AllowedPolicies    string   `json:"allowed_policies"`
-	DisallowedPolicies string   `json:"disallowed_policies"`
-	Orphan             bool     `json:"orphan"`
-	Period             string   `json:"period"`
-	Renewable          bool     `json:"renewable"`
-	ExplicitMaxTTL     int      `json:"explicit_max_ttl"`
-	PathSuffix         string   `json:"path_suffix"`
-	BoundCIDRs         []string `json:"bound_cidrs"`
+// A TokenRole describes a role of the token auth backend. The same type
+// is used for creating, reading, and updating a role, so that roles can be
+// managed as code. TTL and period values are expressed in seconds.
+//
+// More information about token roles can be found here:
+// https://www.vaultproject.io/api/auth/token/index.html#create-update-token-role
+type TokenRole struct {
+	Name                 string   `json:"name"`
+	AllowedPolicies      []string `json:"allowed_policies"`
+	DisallowedPolicies   []string `json:"disallowed_policies"`
+	AllowedEntityAliases []string `json:"allowed_entity_aliases"`
+	Orphan               bool     `json:"orphan"`
+	Renewable            bool     `json:"renewable"`
+	PathSuffix           string   `json:"path_suffix"`
+	BoundCIDRs           []string `json:"token_bound_cidrs"`
+	ExplicitMaxTTL       int      `json:"token_explicit_max_ttl"`
+	Period               int      `json:"token_period"`
+	NoDefaultPolicy      bool     `json:"token_no_default_policy"`
+	NumUses              int      `json:"token_num_uses"`
+	Type                 string   `json:"token_type,omitempty"`
 }
 
-func (c *client) CreateTokenRole(roleData TokenRoleOptions) error {
-	bs, err := json.Marshal(roleData)
+func (c *client) CreateTokenRole(role TokenRole) error {
+	bs, err := json.Marshal(role)
 	if err != nil {
 		return errors.Wrap(err, "marshalling role data to JSON request body")
 	}
 	c.opts.Logger.Printf("role-create request: %v", string(bs))
 
-	requestPath := fmt.Sprintf("/v1/auth/token/roles/%s", roleData.Name)
+	requestPath := fmt.Sprintf("/v1/auth/token/roles/%s", role.Name)
 	if err := c.post(requestPath, string(bs), nil); err != nil {
 		return errors.Wrapf(err, "creating role at %q", requestPath)
 	}
@@ -227,28 +238,32 @@ func (c *client) CreateTokenRole(roleData TokenRoleOptions) error {
 	return nil
 }
 
-type lookedUpTokenRoleWrapper struct {
-	Data LookedUpTokenRole `json:"data"`
+type tokenRoleWrapper struct {
+	Data TokenRole `json:"data"`
 }
 
-type LookedUpTokenRole struct {
-	AllowedPolicies    []string `json:"allowed_policies"`
-	DisallowedPolicies []string `json:"disallowed_policies"`
-	ExplicitMaxTTL     int      `json:"explicit_max_ttl"`
-	Name               string   `json:"name"`
-	Orphan             bool     `json:"orphan"`
-	PathSuffix         string   `json:"path_suffix"`
-	Period             int      `json:"period"`
-	Renewable          bool     `json:"renewable"`
+func (c *client) LookupTokenRole(name string) (TokenRole, error) {
+	var tokenRoleWrapper tokenRoleWrapper
+	requestPath := fmt.Sprintf("/v1/auth/token/roles/%s", name)
+	if err := c.get(requestPath, &tokenRoleWrapper); err != nil {
+		return TokenRole{}, errors.Wrapf(err, "failed to look up role")
+	}
+	return tokenRoleWrapper.Data, nil
 }
 
-func (c *client) LookupTokenRole(name string) (LookedUpTokenRole, error) {
-	var lookedUpTokenRoleWrapper lookedUpTokenRoleWrapper
-	requestPath := fmt.Sprintf("/v1/auth/token/roles/%s", name)
-	if err := c.get(requestPath, &lookedUpTokenRoleWrapper); err != nil {
-		return LookedUpTokenRole{}, errors.Wrapf(err, "failed to look up role")
+// UpdateTokenRole looks up the existing role called name, applies update to
+// it, and writes the merged result back to vault. Fields not modified by
+// update retain their current values.
+func (c *client) UpdateTokenRole(name string, update func(*TokenRole)) error {
+	role, err := c.LookupTokenRole(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update role %q", name)
 	}
-	return lookedUpTokenRoleWrapper.Data, nil
+
+	update(&role)
+	role.Name = name
+
+	return c.CreateTokenRole(role)
 }
 
 func (c *client) DeleteTokenRole(name string) error {
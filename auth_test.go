@@ -1,7 +1,6 @@
 package vaultapi
 
 import (
-	"strings"
 	"testing"
 	"time"
 
@@ -81,16 +80,20 @@ func Test_Renew_Renewable(t *testing.T) {
 func Test_TokenRole(t *testing.T) {
 	clientWithPerm := getClient(t, rootTokener)
 	clientWithoutPerm := getClient(t, renewableTokener)
-	roleOpts := TokenRoleOptions{
-		Name:               "provisioner-role",
-		AllowedPolicies:    "p1,p2",
-		DisallowedPolicies: "p3,p4",
-		Orphan:             true,
-		Period:             "10s",
-		Renewable:          true,
-		ExplicitMaxTTL:     12,
-		PathSuffix:         "suffix",
-		BoundCIDRs:         []string{"10,0,0,0/8"},
+	roleOpts := TokenRole{
+		Name:                 "provisioner-role",
+		AllowedPolicies:      []string{"p1", "p2"},
+		DisallowedPolicies:   []string{"p3", "p4"},
+		AllowedEntityAliases: []string{"alias1"},
+		Orphan:               true,
+		Period:               10,
+		Renewable:            true,
+		ExplicitMaxTTL:       12,
+		PathSuffix:           "suffix",
+		BoundCIDRs:           []string{"10.0.0.0/8"},
+		NoDefaultPolicy:      true,
+		NumUses:              3,
+		Type:                 "service",
 	}
 
 	// Delete the role, in case it exists
@@ -125,16 +128,28 @@ func Test_TokenRole(t *testing.T) {
 	require.Equal(t, []string{"my_role1", roleOpts.Name}, roles)
 
 	// Check that the correct role details came back
-	require.Equal(t, roleOpts.AllowedPolicies, strings.Join(lookedUpTokenRole.AllowedPolicies, ","))
-	require.Equal(t, roleOpts.DisallowedPolicies, strings.Join(lookedUpTokenRole.DisallowedPolicies, ","))
-	require.Equal(t, roleOpts.ExplicitMaxTTL, lookedUpTokenRole.ExplicitMaxTTL)
-	require.Equal(t, roleOpts.Name, lookedUpTokenRole.Name)
-	require.Equal(t, roleOpts.Orphan, lookedUpTokenRole.Orphan)
-	require.Equal(t, roleOpts.PathSuffix, lookedUpTokenRole.PathSuffix)
-	period, err := time.ParseDuration(roleOpts.Period)
+	require.Equal(t, roleOpts, lookedUpTokenRole)
+
+	// Can't update the role without permission
+	require.Error(t, clientWithoutPerm.UpdateTokenRole(roleOpts.Name, func(role *TokenRole) {
+		role.Renewable = false
+	}))
+
+	// Can update the role with permission, keeping unmodified fields
+	require.NoError(t, clientWithPerm.UpdateTokenRole(roleOpts.Name, func(role *TokenRole) {
+		role.Renewable = false
+		role.AllowedPolicies = []string{"p5"}
+	}))
+	updatedTokenRole, err := clientWithPerm.LookupTokenRole(roleOpts.Name)
 	require.NoError(t, err)
-	require.Equal(t, int(period.Seconds()), lookedUpTokenRole.Period)
-	require.Equal(t, roleOpts.Renewable, lookedUpTokenRole.Renewable)
+	require.False(t, updatedTokenRole.Renewable)
+	require.Equal(t, []string{"p5"}, updatedTokenRole.AllowedPolicies)
+	require.Equal(t, roleOpts.DisallowedPolicies, updatedTokenRole.DisallowedPolicies)
+	require.Equal(t, roleOpts.PathSuffix, updatedTokenRole.PathSuffix)
+	require.Equal(t, roleOpts.Period, updatedTokenRole.Period)
+
+	// Can't update a role that does not exist
+	require.Error(t, clientWithPerm.UpdateTokenRole("noexist", func(*TokenRole) {}))
 
 	// Delete the role
 	require.NoError(t, clientWithPerm.DeleteTokenRole(roleOpts.Name))
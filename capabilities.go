@@ -0,0 +1,135 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type capabilitiesMultiWrapper struct {
+	Data map[string][]string `json:"data"`
+}
+
+// capabilitiesMulti requests the capabilities on every path at once, and
+// picks the capabilities of each path out of the response, which also
+// contains the deprecated "capabilities" key
+func (c *client) capabilitiesMulti(endpoint string, body map[string]interface{}, paths []string) (map[string][]string, error) {
+	body["paths"] = paths
+
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapper capabilitiesMultiWrapper
+	if err := c.post(endpoint, string(bs), &wrapper); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(paths))
+	for _, path := range paths {
+		caps, exists := wrapper.Data[path]
+		if !exists {
+			caps = wrapper.Data[strings.TrimPrefix(path, "/")]
+		}
+		sorted := append([]string(nil), caps...)
+		sort.Strings(sorted)
+		result[path] = sorted
+	}
+
+	return result, nil
+}
+
+func (c *client) SelfCapabilitiesMulti(paths []string) (map[string][]string, error) {
+	caps, err := c.capabilitiesMulti("/v1/sys/capabilities-self", map[string]interface{}{}, paths)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read self token capabilities for %v", paths)
+	}
+	return caps, nil
+}
+
+func (c *client) TokenCapabilitiesMulti(paths []string, token string) (map[string][]string, error) {
+	caps, err := c.capabilitiesMulti("/v1/sys/capabilities", map[string]interface{}{"token": token}, paths)
+	if err != nil {
+		// do not provide token anywhere
+		return nil, errors.Wrapf(err, "failed to read token capabilities for %v", paths)
+	}
+	return caps, nil
+}
+
+func (c *client) AccessorCapabilitiesMulti(paths []string, accessor string) (map[string][]string, error) {
+	caps, err := c.capabilitiesMulti("/v1/sys/capabilities-accessor", map[string]interface{}{"accessor": accessor}, paths)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read accessor capabilities for %q at %v", accessor, paths)
+	}
+	return caps, nil
+}
+
+// MissingCapabilitiesError is returned by RequireCapabilities when the
+// token is missing some of the required capabilities.
+type MissingCapabilitiesError struct {
+	// Missing contains the capabilities which are
+	// required but not granted, keyed by path.
+	Missing map[string][]string
+}
+
+func (e *MissingCapabilitiesError) Error() string {
+	paths := make([]string, 0, len(e.Missing))
+	for path := range e.Missing {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	lines := make([]string, 0, len(paths))
+	for _, path := range paths {
+		lines = append(lines, fmt.Sprintf("%s: missing %s", path, strings.Join(e.Missing[path], ", ")))
+	}
+
+	return fmt.Sprintf("token is missing capabilities on %d path(s): %s", len(paths), strings.Join(lines, "; "))
+}
+
+// missingCapabilities compares the required capabilities
+// of each path to the capabilities actually granted
+func missingCapabilities(required, granted map[string][]string) map[string][]string {
+	missing := make(map[string][]string)
+	for path, caps := range required {
+		have := granted[path]
+		if containsString(have, "root") {
+			continue
+		}
+		for _, capability := range caps {
+			if !containsString(have, capability) || containsString(have, CapabilityDeny) {
+				missing[path] = append(missing[path], capability)
+			}
+		}
+		sort.Strings(missing[path])
+	}
+	return missing
+}
+
+// RequireCapabilities checks in a single request that the token of the
+// client has every one of the required capabilities, keyed by path. This is
+// useful as a preflight check when a service starts up. If any capabilities
+// are missing, the returned error is a *MissingCapabilitiesError describing
+// every one of them.
+func (c *client) RequireCapabilities(required map[string][]string) error {
+	paths := make([]string, 0, len(required))
+	for path := range required {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	granted, err := c.SelfCapabilitiesMulti(paths)
+	if err != nil {
+		return err
+	}
+
+	if missing := missingCapabilities(required, granted); len(missing) > 0 {
+		return &MissingCapabilitiesError{Missing: missing}
+	}
+
+	return nil
+}
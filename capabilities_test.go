@@ -0,0 +1,69 @@
+package vaultapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_missingCapabilities(t *testing.T) {
+	missing := missingCapabilities(
+		map[string][]string{
+			"secret/my/stuff/a": {"read", "update"},
+			"sys/mounts":        {"read"},
+			"secret/other":      {"list", "delete"},
+			"auth/token/create": {"update"},
+		},
+		map[string][]string{
+			"secret/my/stuff/a": {"create", "read", "update"},
+			"sys/mounts":        {"deny"},
+			"secret/other":      {"list"},
+			"auth/token/create": {"root"},
+		},
+	)
+	require.Equal(t, map[string][]string{
+		"sys/mounts":   {"read"},
+		"secret/other": {"delete"},
+	}, missing)
+
+	err := &MissingCapabilitiesError{Missing: missing}
+	require.Equal(t, "token is missing capabilities on 2 path(s): secret/other: missing delete; sys/mounts: missing read", err.Error())
+}
+
+func Test_Client_SelfCapabilitiesMulti(t *testing.T) {
+	client := getClient(t, nonRenewableTokener)
+	caps, err := client.SelfCapabilitiesMulti([]string{"secret/my/stuff/a", "sys/mounts"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"create", "list", "read", "update"}, caps["secret/my/stuff/a"])
+	require.Equal(t, []string{"deny"}, caps["sys/mounts"])
+}
+
+func Test_Client_TokenCapabilitiesMulti(t *testing.T) {
+	client := getClient(t, rootTokener)
+	token, err := nonRenewableTokener().Token()
+	require.NoError(t, err)
+	caps, err := client.TokenCapabilitiesMulti([]string{"secret/my/stuff/a", "sys/mounts"}, token)
+	require.NoError(t, err)
+	require.Equal(t, []string{"create", "list", "read", "update"}, caps["secret/my/stuff/a"])
+	require.Equal(t, []string{"deny"}, caps["sys/mounts"])
+}
+
+func Test_Client_RequireCapabilities(t *testing.T) {
+	client := getClient(t, nonRenewableTokener)
+
+	err := client.RequireCapabilities(map[string][]string{
+		"secret/my/stuff/a":      {"read", "update"},
+		"auth/token/lookup-self": {"read"},
+	})
+	require.NoError(t, err)
+
+	err = client.RequireCapabilities(map[string][]string{
+		"secret/my/stuff/a": {"delete"},
+		"sys/mounts":        {"read"},
+	})
+	require.Error(t, err)
+	missing, ok := err.(*MissingCapabilitiesError)
+	require.True(t, ok)
+	require.Equal(t, []string{"delete"}, missing.Missing["secret/my/stuff/a"])
+	require.Equal(t, []string{"read"}, missing.Missing["sys/mounts"])
+}
@@ -0,0 +1,263 @@
+package vaultapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A CertManager keeps a certificate issued by the PKI secrets engine
+// up to date, renewing it in the background ahead of its expiration. The
+// certificate is served through a tls.Config, so that connections always
+// use the latest certificate without being restarted.
+type CertManager interface {
+	// TLSConfig returns a tls.Config for use by both servers and clients
+	// of mutual TLS, which may be further modified by the caller. Servers
+	// pick up changes to the trust pool for each new connection, whereas
+	// clients use the trust pool as of when TLSConfig was called.
+	TLSConfig() *tls.Config
+
+	// GetCertificate returns the current certificate, and is
+	// suitable for use as tls.Config.GetCertificate.
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+
+	// GetClientCertificate returns the current certificate, and is
+	// suitable for use as tls.Config.GetClientCertificate.
+	GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+
+	// Certificate returns the current certificate.
+	Certificate() IssuedCertificate
+
+	// Pool returns the trust pool containing the CA of the
+	// PKI secrets engine.
+	Pool() *x509.CertPool
+
+	// Close stops renewing the certificate.
+	Close() error
+}
+
+// A CertEvent describes an attempt by a CertManager to
+// rotate its certificate.
+type CertEvent struct {
+	// Certificate is the newly issued certificate,
+	// or nil if the rotation failed.
+	Certificate *x509.Certificate
+
+	// Previous is the certificate being replaced.
+	Previous *x509.Certificate
+
+	// Err describes why the rotation failed, in which case the
+	// rotation is retried after CertManagerOptions.RetryInterval.
+	Err error
+}
+
+// CertManagerOptions are used to configure a CertManager upon creation.
+type CertManagerOptions struct {
+	// Role is the PKI role with which certificates are issued.
+	Role string
+
+	// Certificate configures each certificate that is issued.
+	Certificate CertificateOptions
+
+	// Jitter is the fraction of the lifetime of the certificate by which
+	// renewal is randomly made earlier, so that many instances of a service
+	// do not renew at once. The default is 0.1, and renewal otherwise
+	// happens when two thirds of the lifetime has elapsed.
+	Jitter float64
+
+	// RetryInterval is how long to wait before retrying a failed
+	// renewal. The default is 10 seconds.
+	RetryInterval time.Duration
+
+	// Events is called after each attempt to rotate the certificate.
+	Events func(CertEvent)
+
+	// Logger may be optionally configured as an output for trace
+	// level logging produced by the CertManager.
+	Logger *log.Logger
+}
+
+type certManager struct {
+	pki  PKI
+	opts CertManagerOptions
+
+	lock        sync.Mutex
+	issued      IssuedCertificate
+	certificate *tls.Certificate
+	pool        *x509.CertPool
+
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewCertManager creates a CertManager which issues certificates using pki.
+// The first certificate is issued before NewCertManager returns, so that a
+// misconfiguration is reported immediately. The background goroutine runs
+// until Close is called.
+func NewCertManager(pki PKI, opts CertManagerOptions) (CertManager, error) {
+	if opts.Logger == nil {
+		opts.Logger = log.New(ioutil.Discard, "", 0)
+	}
+
+	if opts.Events == nil {
+		opts.Events = func(CertEvent) {}
+	}
+
+	if opts.Jitter <= 0 {
+		opts.Jitter = 0.1
+	}
+
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = 10 * time.Second
+	}
+
+	m := &certManager{
+		pki:     pki,
+		opts:    opts,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	if err := m.rotate(); err != nil {
+		return nil, err
+	}
+
+	go m.run()
+
+	return m, nil
+}
+
+// rotate issues a new certificate and refreshes the trust pool
+func (m *certManager) rotate() error {
+	issued, err := m.pki.IssueCertificate(m.opts.Role, m.opts.Certificate)
+	if err != nil {
+		return err
+	}
+
+	ca, err := m.pki.ReadCA()
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	for _, chained := range issued.Chain {
+		pool.AddCert(chained)
+	}
+
+	certificate := issued.TLSCertificate()
+
+	m.lock.Lock()
+	m.issued = issued
+	m.certificate = &certificate
+	m.pool = pool
+	m.lock.Unlock()
+
+	m.opts.Logger.Printf("cert manager issued certificate %s, expires %s", issued.SerialNumber, issued.Certificate.NotAfter)
+	return nil
+}
+
+// renewAt returns when certificate should be renewed, which is when two
+// thirds of its lifetime has elapsed, made earlier by a random jitter
+func (m *certManager) renewAt(certificate *x509.Certificate) time.Time {
+	lifetime := certificate.NotAfter.Sub(certificate.NotBefore)
+	renew := certificate.NotBefore.Add(lifetime * 2 / 3)
+
+	if jitter := int64(float64(lifetime) * m.opts.Jitter); jitter > 0 {
+		renew = renew.Add(-time.Duration(rand.Int63n(jitter)))
+	}
+
+	return renew
+}
+
+func (m *certManager) run() {
+	defer close(m.stopped)
+
+	next := m.renewAt(m.Certificate().Certificate)
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-m.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		previous := m.Certificate().Certificate
+		if err := m.rotate(); err != nil {
+			m.opts.Logger.Printf("cert manager failed to renew certificate: %v", err)
+			m.opts.Events(CertEvent{
+				Previous: previous,
+				Err:      errors.Wrap(err, "failed to renew certificate"),
+			})
+			next = time.Now().Add(m.opts.RetryInterval)
+			continue
+		}
+
+		current := m.Certificate().Certificate
+		m.opts.Events(CertEvent{
+			Certificate: current,
+			Previous:    previous,
+		})
+		next = m.renewAt(current)
+	}
+}
+
+func (m *certManager) TLSConfig() *tls.Config {
+	config := &tls.Config{
+		GetCertificate:       m.GetCertificate,
+		GetClientCertificate: m.GetClientCertificate,
+		RootCAs:              m.Pool(),
+		ClientCAs:            m.Pool(),
+		ClientAuth:           tls.RequireAndVerifyClientCert,
+	}
+
+	// each connection uses the config as modified by the caller,
+	// e.g. with NextProtos or MinVersion set, with the current pool
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := config.Clone()
+		c.GetConfigForClient = nil
+		c.ClientCAs = m.Pool()
+		return c, nil
+	}
+	return config
+}
+
+func (m *certManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.certificate, nil
+}
+
+func (m *certManager) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.certificate, nil
+}
+
+func (m *certManager) Certificate() IssuedCertificate {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.issued
+}
+
+func (m *certManager) Pool() *x509.CertPool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.pool
+}
+
+func (m *certManager) Close() error {
+	m.once.Do(func() {
+		close(m.stop)
+	})
+	<-m.stopped
+	return nil
+}
@@ -0,0 +1,279 @@
+package vaultapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakePKI issues short lived certificates from a local CA
+type fakePKI struct {
+	PKI // not implemented
+
+	lifetime time.Duration
+	caKey    *ecdsa.PrivateKey
+	ca       *x509.Certificate
+
+	lock   sync.Mutex
+	serial int64
+	fail   bool
+}
+
+func newFakePKI(t *testing.T, lifetime time.Duration) *fakePKI {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &fakePKI{lifetime: lifetime, caKey: key, ca: ca, serial: 1}
+}
+
+func (f *fakePKI) IssueCertificate(role string, opts CertificateOptions) (IssuedCertificate, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.fail {
+		return IssuedCertificate{}, errors.New("vault is sealed")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return IssuedCertificate{}, err
+	}
+
+	var ips []net.IP
+	for _, ip := range opts.IPSANs {
+		ips = append(ips, net.ParseIP(ip))
+	}
+
+	f.serial++
+	now := time.Now().Truncate(time.Second)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(f.serial),
+		Subject:      pkix.Name{CommonName: opts.CommonName},
+		DNSNames:     []string{opts.CommonName},
+		IPAddresses:  ips,
+		NotBefore:    now,
+		NotAfter:     now.Add(f.lifetime),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, f.ca, key.Public(), f.caKey)
+	if err != nil {
+		return IssuedCertificate{}, err
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return IssuedCertificate{}, err
+	}
+
+	return IssuedCertificate{
+		SignedCertificate: SignedCertificate{
+			Certificate:  certificate,
+			Chain:        []*x509.Certificate{f.ca},
+			SerialNumber: fmt.Sprintf("%02x", f.serial),
+		},
+		PrivateKey:     key,
+		PrivateKeyType: "ec",
+	}, nil
+}
+
+func (f *fakePKI) ReadCA() (*x509.Certificate, error) {
+	return f.ca, nil
+}
+
+func (f *fakePKI) setFail(fail bool) {
+	f.lock.Lock()
+	f.fail = fail
+	f.lock.Unlock()
+}
+
+func Test_CertManager_mTLS(t *testing.T) {
+	fake := newFakePKI(t, time.Hour)
+	manager, err := NewCertManager(fake, CertManagerOptions{
+		Role:        "web",
+		Certificate: CertificateOptions{CommonName: "localhost", IPSANs: []string{"127.0.0.1"}},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, manager.Close()) }()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = manager.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: manager.TLSConfig()}}
+	response, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	bs, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, "localhost", string(bs))
+}
+
+func Test_CertManager_TLSConfig_modified(t *testing.T) {
+	fake := newFakePKI(t, time.Hour)
+	manager, err := NewCertManager(fake, CertManagerOptions{
+		Role:        "web",
+		Certificate: CertificateOptions{CommonName: "localhost", IPSANs: []string{"127.0.0.1"}},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, manager.Close()) }()
+
+	config := manager.TLSConfig()
+	config.NextProtos = []string{"h2", "http/1.1"}
+	config.MinVersion = tls.VersionTLS13
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	accepted := make(chan tls.ConnectionState, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if tlsConn.Handshake() == nil {
+			accepted <- tlsConn.ConnectionState()
+		}
+		close(accepted)
+	}()
+
+	clientConfig := manager.TLSConfig()
+	clientConfig.NextProtos = []string{"h2"}
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	state := <-accepted
+	require.Equal(t, "h2", state.NegotiatedProtocol)
+	require.Equal(t, uint16(tls.VersionTLS13), state.Version)
+
+	// the server refuses clients below its minimum version
+	clientConfig = manager.TLSConfig()
+	clientConfig.MaxVersion = tls.VersionTLS12
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	_, err = tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	require.Error(t, err)
+}
+
+func Test_CertManager_rotation(t *testing.T) {
+	fake := newFakePKI(t, time.Second)
+
+	events := make(chan CertEvent, 100)
+	manager, err := NewCertManager(fake, CertManagerOptions{
+		Role:          "web",
+		Certificate:   CertificateOptions{CommonName: "localhost"},
+		RetryInterval: 50 * time.Millisecond,
+		Events: func(event CertEvent) {
+			select {
+			case events <- event:
+			default:
+			}
+		},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, manager.Close()) }()
+
+	first := manager.Certificate()
+
+	event := <-events
+	require.NoError(t, event.Err)
+	require.Equal(t, first.Certificate, event.Previous)
+	require.NotEqual(t, first.Certificate.SerialNumber, event.Certificate.SerialNumber)
+
+	served, err := manager.GetCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Certificate, served.Leaf)
+
+	fake.setFail(true)
+	for event = <-events; event.Err == nil; event = <-events {
+	}
+	require.Nil(t, event.Certificate)
+	previous := event.Previous
+
+	fake.setFail(false)
+	for event = <-events; event.Err != nil; event = <-events {
+	}
+	require.Equal(t, previous, event.Previous)
+	require.NotNil(t, event.Certificate)
+}
+
+func Test_CertManager_renewAt(t *testing.T) {
+	m := &certManager{opts: CertManagerOptions{Jitter: 0.1}}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	certificate := &x509.Certificate{NotBefore: start, NotAfter: start.Add(90 * time.Minute)}
+
+	for i := 0; i < 100; i++ {
+		renew := m.renewAt(certificate)
+		require.False(t, renew.After(start.Add(60*time.Minute)))
+		require.True(t, renew.After(start.Add(51*time.Minute)))
+	}
+}
+
+func Test_CertManager_issueFailure(t *testing.T) {
+	fake := newFakePKI(t, time.Hour)
+	fake.setFail(true)
+	_, err := NewCertManager(fake, CertManagerOptions{Role: "web"})
+	require.Error(t, err)
+}
+
+func Test_CertManager_DevVault(t *testing.T) {
+	pki := getPKI(t)
+
+	manager, err := NewCertManager(pki, CertManagerOptions{
+		Role:        "test",
+		Certificate: CertificateOptions{CommonName: "localhost", IPSANs: []string{"127.0.0.1"}},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, manager.Close()) }()
+
+	config := manager.TLSConfig()
+	certificate, err := config.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Equal(t, "localhost", certificate.Leaf.Subject.CommonName)
+
+	_, err = certificate.Leaf.Verify(x509.VerifyOptions{
+		Roots:     manager.Pool(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+}
@@ -19,6 +19,7 @@ import (
 
 const (
 	headerVaultToken  = "X-Vault-Token"
+	headerWrapTTL     = "X-Vault-Wrap-TTL"
 	headerContentType = "Content-Type"
 	mimeJSON          = "application/json"
 	mimeText          = "text/plain"
@@ -34,6 +35,22 @@ type Client interface {
 	Auth
 	KV
 	Sys
+
+	// Logical provides raw access to arbitrary paths in vault. Any
+	// options are applied to every request made through the Logical.
+	Logical(opts ...RequestOption) Logical
+
+	// Transit provides access to the transit secrets engine enabled
+	// at mount, or at "transit" if mount is empty.
+	Transit(mount string) Transit
+
+	// PKI provides access to the PKI secrets engine enabled
+	// at mount, or at "pki" if mount is empty.
+	PKI(mount string) PKI
+
+	// Database provides access to the database secrets engine
+	// enabled at mount, or at "database" if mount is empty.
+	Database(mount string) Database
 }
 
 var (
@@ -114,6 +131,49 @@ type client struct {
 
 	tokener    Tokener
 	httpClient *http.Client
+
+	// additional headers set on every request
+	headers http.Header
+
+	// err is set when a RequestOption is invalid, and
+	// is returned by every request instead
+	err error
+}
+
+// A RequestOption modifies the requests sent to vault.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	headers http.Header
+	err     error
+}
+
+// with returns a copy of c which applies opts to every request
+func (c *client) with(opts ...RequestOption) *client {
+	if len(opts) == 0 {
+		return c
+	}
+
+	ro := requestOptions{headers: make(http.Header)}
+	for key, values := range c.headers {
+		ro.headers[key] = values
+	}
+	for _, opt := range opts {
+		opt(&ro)
+	}
+
+	modified := *c
+	modified.headers = ro.headers
+	if modified.err == nil {
+		modified.err = ro.err
+	}
+	return &modified
+}
+
+func (c *client) setHeaders(request *http.Request) {
+	for key, values := range c.headers {
+		request.Header[key] = values
+	}
 }
 
 func (c *client) token() (string, error) {
@@ -145,6 +205,9 @@ func fixup(prefix, path string, params ...[2]string) string {
 }
 
 func (c *client) get(path string, i interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
 	for _, address := range c.opts.Servers {
 		err := c.singleGet(address, path, i)
 		if err == ErrPathNotFound {
@@ -174,6 +237,7 @@ func (c *client) singleGet(address, path string, i interface{}) error {
 
 	request.Header.Set(headerVaultToken, token)
 	request.Header.Set(headerContentType, mimeText)
+	c.setHeaders(request)
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
@@ -200,6 +264,9 @@ func (c *client) singleGet(address, path string, i interface{}) error {
 }
 
 func (c *client) list(path string, i interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
 	for _, address := range c.opts.Servers {
 		err := c.singleList(address, path, i)
 		if err == ErrPathNotFound {
@@ -229,6 +296,7 @@ func (c *client) singleList(address, path string, i interface{}) error {
 
 	request.Header.Set(headerVaultToken, token)
 	request.Header.Set(headerContentType, mimeJSON)
+	c.setHeaders(request)
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
@@ -257,8 +325,22 @@ func (c *client) singleList(address, path string, i interface{}) error {
 }
 
 func (c *client) post(path, body string, i interface{}) error {
+	return c.postCodes(path, body, i, false)
+}
+
+// postPartial is like post, but also reads the response when vault responds
+// with 400 Bad Request, which is how batch requests report that some of their
+// items could not be processed.
+func (c *client) postPartial(path, body string, i interface{}) error {
+	return c.postCodes(path, body, i, true)
+}
+
+func (c *client) postCodes(path, body string, i interface{}, partial bool) error {
+	if c.err != nil {
+		return c.err
+	}
 	for _, address := range c.opts.Servers {
-		err := c.singlePost(address, path, body, i)
+		err := c.singlePost(address, path, body, i, partial)
 		if err == ErrPathNotFound {
 			c.opts.Logger.Printf("POST request for unknown path: %q", path)
 			return ErrPathNotFound
@@ -271,7 +353,7 @@ func (c *client) post(path, body string, i interface{}) error {
 	return errors.Errorf("all attempts for POST request failed to: %v", c.opts.Servers)
 }
 
-func (c *client) singlePost(address, path, body string, i interface{}) error {
+func (c *client) singlePost(address, path, body string, i interface{}, partial bool) error {
 	url := address + path
 
 	request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
@@ -286,6 +368,7 @@ func (c *client) singlePost(address, path, body string, i interface{}) error {
 
 	request.Header.Set(headerVaultToken, token)
 	request.Header.Set(headerContentType, mimeJSON)
+	c.setHeaders(request)
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
@@ -298,11 +381,13 @@ func (c *client) singlePost(address, path, body string, i interface{}) error {
 		return ErrPathNotFound
 	}
 
-	if response.StatusCode >= 400 {
+	badRequest := partial && response.StatusCode == http.StatusBadRequest
+	if response.StatusCode >= 400 && !badRequest {
 		return errors.Errorf("bad status code: %d, url: %s", response.StatusCode, url)
 	}
 
-	if i != nil {
+	// vault may respond without content, e.g. after writing a secret
+	if i != nil && response.StatusCode != http.StatusNoContent {
 		// read the response iff we have something to unmarshal it into
 		defer ignore.Drain(response.Body)
 		if err := json.NewDecoder(response.Body).Decode(i); err != nil {
@@ -313,7 +398,44 @@ func (c *client) singlePost(address, path, body string, i interface{}) error {
 	return nil
 }
 
+// unauthenticated makes a request without a token to the one server at
+// address, for the endpoints vault serves before it can issue tokens
+func (c *client) unauthenticated(method, address, path, body string, i interface{}) error {
+	url := address + path
+
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to build %s request to %q", method, url)
+	}
+
+	request.Header.Set(headerContentType, mimeJSON)
+	c.setHeaders(request)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to execute %s request to %q", method, url)
+	}
+	defer ignore.Drain(response.Body)
+
+	if response.StatusCode == http.StatusNotFound {
+		return ErrPathNotFound
+	}
+
+	if response.StatusCode >= 400 {
+		return errors.Errorf("bad status code: %d, url: %s", response.StatusCode, url)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(i); err != nil {
+		return errors.Wrapf(err, "failed to read response from %q", url)
+	}
+
+	return nil
+}
+
 func (c *client) put(path, body string) error {
+	if c.err != nil {
+		return c.err
+	}
 	for _, address := range c.opts.Servers {
 		err := c.singlePut(address, path, body)
 		if err == ErrPathNotFound {
@@ -343,6 +465,7 @@ func (c *client) singlePut(address, path, body string) error {
 
 	request.Header.Set(headerVaultToken, token)
 	request.Header.Set(headerContentType, mimeJSON)
+	c.setHeaders(request)
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
@@ -395,11 +518,14 @@ func (c *client) delete(path string) error {
 }
 
 func (c *client) deleteKey(path string) error {
+	if c.err != nil {
+		return c.err
+	}
 	for _, address := range c.opts.Servers {
 		err := c.singleDelete(address, path)
 		if err == ErrPathNotFound {
 			c.opts.Logger.Printf("DELETE request to unknown path: %q", path)
-			continue
+			return ErrPathNotFound
 		} else if err != nil {
 			c.opts.Logger.Printf("DELETE request failed: %v", err)
 			continue
@@ -424,6 +550,7 @@ func (c *client) singleDelete(address, path string) error {
 	}
 
 	request.Header.Set(headerVaultToken, token)
+	c.setHeaders(request)
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
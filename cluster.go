@@ -0,0 +1,147 @@
+package vaultapi
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// A NodeStatus contains the status of one vault server, as reported by the
+// server itself. If any of the status requests failed, Err is set and the
+// corresponding fields are left empty.
+type NodeStatus struct {
+	Address    string
+	Health     Health
+	Leader     Leader
+	SealStatus SealStatus
+	Version    string
+	Err        error
+}
+
+// A ClusterStatus summarizes the status of every configured vault server.
+type ClusterStatus struct {
+	// Nodes contains the status of each server, ordered by address.
+	Nodes []NodeStatus
+
+	// LeaderAddress is the address of the active server, if the
+	// servers agree on which server is active.
+	LeaderAddress string
+
+	// Leaders contains each distinct leader address reported by the
+	// servers. There should only ever be one.
+	Leaders []string
+
+	// SplitBrain indicates that the servers disagree on which server is
+	// active, or that more than one server claims to be active.
+	SplitBrain bool
+
+	// Versions contains each distinct version of vault being run.
+	Versions []string
+
+	// VersionSkew indicates that not all servers are running
+	// the same version of vault.
+	VersionSkew bool
+}
+
+func (c *client) ClusterStatus() (ClusterStatus, error) {
+	nodes := make([]NodeStatus, len(c.opts.Servers))
+
+	var wg sync.WaitGroup
+	for i, address := range c.opts.Servers {
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			nodes[i] = c.nodeStatus(address)
+		}(i, address)
+	}
+	wg.Wait()
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Address < nodes[j].Address
+	})
+
+	status := summarize(nodes)
+
+	for _, node := range nodes {
+		if node.Err == nil {
+			return status, nil
+		}
+	}
+	return status, errors.Errorf("failed to read status from: %v", c.opts.Servers)
+}
+
+func (c *client) nodeStatus(address string) NodeStatus {
+	node := NodeStatus{Address: address}
+
+	health, err := c.ServerHealth(address, HealthOptions{})
+	if err != nil {
+		node.Err = errors.Wrap(err, "failed to read health")
+		return node
+	}
+	node.Health = health
+	node.Version = health.Version
+
+	var sealStatus SealStatus
+	if err := c.singleGet(address, "/v1/sys/seal-status", &sealStatus); err != nil {
+		node.Err = errors.Wrap(err, "failed to get sealed status")
+		return node
+	}
+	node.SealStatus = sealStatus
+	if node.Version == "" {
+		node.Version = sealStatus.Version
+	}
+
+	// a sealed server cannot report the leader
+	if sealStatus.Sealed {
+		return node
+	}
+
+	var leader Leader
+	if err := c.singleGet(address, "/v1/sys/leader", &leader); err != nil {
+		node.Err = errors.Wrap(err, "failed to read leader")
+		return node
+	}
+	node.Leader = leader
+
+	return node
+}
+
+func summarize(nodes []NodeStatus) ClusterStatus {
+	status := ClusterStatus{Nodes: nodes}
+
+	leaders := make(map[string]bool)
+	versions := make(map[string]bool)
+	active := 0
+
+	for _, node := range nodes {
+		if node.Version != "" {
+			versions[node.Version] = true
+		}
+		if node.Leader.LeaderAddress != "" {
+			leaders[node.Leader.LeaderAddress] = true
+		}
+		if node.Leader.IsSelf {
+			active++
+		}
+	}
+
+	for leader := range leaders {
+		status.Leaders = append(status.Leaders, leader)
+	}
+	sort.Strings(status.Leaders)
+
+	for version := range versions {
+		status.Versions = append(status.Versions, version)
+	}
+	sort.Strings(status.Versions)
+
+	status.SplitBrain = len(status.Leaders) > 1 || active > 1
+	status.VersionSkew = len(status.Versions) > 1
+
+	if len(status.Leaders) == 1 && !status.SplitBrain {
+		status.LeaderAddress = status.Leaders[0]
+	}
+
+	return status
+}
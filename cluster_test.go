@@ -0,0 +1,76 @@
+package vaultapi
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClusterStatus(t *testing.T) {
+	opts := devOpts()
+	opts.Servers = append(opts.Servers, "http://localhost:1")
+	client, err := New(opts, rootTokener())
+	require.NoError(t, err)
+
+	status, err := client.ClusterStatus()
+	require.NoError(t, err)
+	require.Len(t, status.Nodes, 2)
+
+	unreachable := status.Nodes[0]
+	require.Equal(t, "http://localhost:1", unreachable.Address)
+	require.Error(t, unreachable.Err)
+
+	dev := status.Nodes[1]
+	require.Equal(t, "http://localhost:8200", dev.Address)
+	require.NoError(t, dev.Err)
+	require.False(t, dev.SealStatus.Sealed)
+	require.NotEmpty(t, dev.Version)
+
+	// the dev vault does not run in HA mode
+	require.Empty(t, status.LeaderAddress)
+	require.False(t, status.SplitBrain)
+	require.False(t, status.VersionSkew)
+	require.Equal(t, []string{dev.Version}, status.Versions)
+}
+
+func Test_ClusterStatus_Summarize(t *testing.T) {
+	status := summarize([]NodeStatus{
+		{
+			Address: "https://10.0.0.1:8200",
+			Version: "1.2.3",
+			Leader:  Leader{HAEnabled: true, IsSelf: true, LeaderAddress: "https://10.0.0.1:8200"},
+		},
+		{
+			Address: "https://10.0.0.2:8200",
+			Version: "1.2.3",
+			Leader:  Leader{HAEnabled: true, LeaderAddress: "https://10.0.0.1:8200"},
+		},
+		{
+			Address: "https://10.0.0.3:8200",
+			Err:     errors.New("connection refused"),
+		},
+	})
+	require.Equal(t, "https://10.0.0.1:8200", status.LeaderAddress)
+	require.False(t, status.SplitBrain)
+	require.False(t, status.VersionSkew)
+
+	status = summarize([]NodeStatus{
+		{
+			Address: "https://10.0.0.1:8200",
+			Version: "1.2.3",
+			Leader:  Leader{HAEnabled: true, IsSelf: true, LeaderAddress: "https://10.0.0.1:8200"},
+		},
+		{
+			Address: "https://10.0.0.2:8200",
+			Version: "1.2.4",
+			Leader:  Leader{HAEnabled: true, IsSelf: true, LeaderAddress: "https://10.0.0.2:8200"},
+		},
+	})
+	require.Empty(t, status.LeaderAddress)
+	require.True(t, status.SplitBrain)
+	require.Equal(t, []string{"https://10.0.0.1:8200", "https://10.0.0.2:8200"}, status.Leaders)
+	require.True(t, status.VersionSkew)
+	require.Equal(t, []string{"1.2.3", "1.2.4"}, status.Versions)
+}
@@ -0,0 +1,476 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//go:generate go run github.com/shoenig/mockery3/v3/cmd/mockery3 -interface Database -package vaultapitest
+
+// A Database manages connections to databases and generates credentials
+// for them through the database secrets engine.
+//
+// More information about the database secrets engine can be found here:
+// https://www.vaultproject.io/api/secret/databases/index.html
+type Database interface {
+	// Connections
+	ConfigureConnection(name string, connection DatabaseConnection) error
+	ReadConnection(name string) (DatabaseConnection, error)
+	ListConnections() ([]string, error)
+	DeleteConnection(name string) error
+	ResetConnection(name string) error
+	RotateRootCredentials(name string) error
+
+	// Roles
+	CreateRole(name string, role DatabaseRole) error
+	ReadRole(name string) (DatabaseRole, error)
+	ListRoles() ([]string, error)
+	DeleteRole(name string) error
+	DatabaseCredentials(role string) (DatabaseCredentials, error)
+
+	// Static Roles
+	CreateStaticRole(name string, role DatabaseStaticRole) error
+	ReadStaticRole(name string) (DatabaseStaticRole, error)
+	ListStaticRoles() ([]string, error)
+	DeleteStaticRole(name string) error
+	ReadStaticCredentials(name string) (StaticCredentials, error)
+	RotateRole(name string) error
+}
+
+// A DatabaseConnection configures how vault connects to a database.
+type DatabaseConnection struct {
+	// PluginName is the database plugin used for the connection,
+	// for example "postgresql-database-plugin".
+	PluginName string
+
+	// ConnectionURL is the plugin specific connection string, which may
+	// contain {{username}} and {{password}} templates.
+	ConnectionURL string
+
+	// Username and Password are the root credentials vault uses to
+	// manage the database. Password is never returned by vault.
+	Username string
+	Password string
+
+	// AllowedRoles lists the roles allowed to use the connection,
+	// where "*" allows every role.
+	AllowedRoles []string
+
+	// RootRotationStatements are executed by RotateRootCredentials.
+	RootRotationStatements []string
+
+	// SkipVerify prevents vault from verifying the connection
+	// when it is configured.
+	SkipVerify bool
+
+	// Options are additional plugin specific parameters,
+	// for example "max_open_connections".
+	Options map[string]interface{}
+}
+
+// MarshalJSON encodes connection in the form expected by vault.
+func (connection DatabaseConnection) MarshalJSON() ([]byte, error) {
+	request := make(map[string]interface{}, len(connection.Options)+7)
+	for key, value := range connection.Options {
+		request[key] = value
+	}
+
+	request["plugin_name"] = connection.PluginName
+	request["verify_connection"] = !connection.SkipVerify
+	if connection.ConnectionURL != "" {
+		request["connection_url"] = connection.ConnectionURL
+	}
+	if connection.Username != "" {
+		request["username"] = connection.Username
+	}
+	if connection.Password != "" {
+		request["password"] = connection.Password
+	}
+	if len(connection.AllowedRoles) > 0 {
+		request["allowed_roles"] = strings.Join(connection.AllowedRoles, ",")
+	}
+	if len(connection.RootRotationStatements) > 0 {
+		request["root_rotation_statements"] = connection.RootRotationStatements
+	}
+
+	return json.Marshal(request)
+}
+
+// UnmarshalJSON decodes connection from the form returned by vault.
+func (connection *DatabaseConnection) UnmarshalJSON(bs []byte) error {
+	var response struct {
+		PluginName             string                 `json:"plugin_name"`
+		AllowedRoles           []string               `json:"allowed_roles"`
+		RootRotationStatements []string               `json:"root_credentials_rotate_statements"`
+		VerifyConnection       *bool                  `json:"verify_connection"`
+		ConnectionDetails      map[string]interface{} `json:"connection_details"`
+	}
+
+	if err := json.Unmarshal(bs, &response); err != nil {
+		return err
+	}
+
+	*connection = DatabaseConnection{
+		PluginName:             response.PluginName,
+		AllowedRoles:           response.AllowedRoles,
+		RootRotationStatements: response.RootRotationStatements,
+	}
+
+	// vault verifies connections unless told otherwise
+	if response.VerifyConnection != nil {
+		connection.SkipVerify = !*response.VerifyConnection
+	}
+
+	for key, value := range response.ConnectionDetails {
+		switch key {
+		case "connection_url":
+			connection.ConnectionURL, _ = value.(string)
+		case "username":
+			connection.Username, _ = value.(string)
+		default:
+			if connection.Options == nil {
+				connection.Options = make(map[string]interface{})
+			}
+			connection.Options[key] = value
+		}
+	}
+
+	return nil
+}
+
+// A DatabaseRole describes how credentials are created in the
+// database when they are requested through the role.
+type DatabaseRole struct {
+	DBName               string        `json:"db_name"`
+	DefaultTTL           time.Duration `json:"-"`
+	MaxTTL               time.Duration `json:"-"`
+	CreationStatements   []string      `json:"creation_statements"`
+	RevocationStatements []string      `json:"revocation_statements,omitempty"`
+	RollbackStatements   []string      `json:"rollback_statements,omitempty"`
+	RenewStatements      []string      `json:"renew_statements,omitempty"`
+}
+
+// the fields of DatabaseRole without the custom json methods
+type databaseRoleFields DatabaseRole
+
+// MarshalJSON encodes role in the form expected by vault.
+func (role DatabaseRole) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		databaseRoleFields
+		DefaultTTL string `json:"default_ttl,omitempty"`
+		MaxTTL     string `json:"max_ttl,omitempty"`
+	}{
+		databaseRoleFields: databaseRoleFields(role),
+		DefaultTTL:         ttlString(role.DefaultTTL),
+		MaxTTL:             ttlString(role.MaxTTL),
+	})
+}
+
+// UnmarshalJSON decodes role from the form returned by vault.
+func (role *DatabaseRole) UnmarshalJSON(bs []byte) error {
+	response := struct {
+		*databaseRoleFields
+		DefaultTTL int `json:"default_ttl"`
+		MaxTTL     int `json:"max_ttl"`
+	}{databaseRoleFields: (*databaseRoleFields)(role)}
+
+	if err := json.Unmarshal(bs, &response); err != nil {
+		return err
+	}
+
+	role.DefaultTTL = time.Duration(response.DefaultTTL) * time.Second
+	role.MaxTTL = time.Duration(response.MaxTTL) * time.Second
+	return nil
+}
+
+// A DatabaseStaticRole maps to an existing user in the database,
+// whose password is rotated by vault every RotationPeriod.
+type DatabaseStaticRole struct {
+	DBName             string        `json:"db_name"`
+	Username           string        `json:"username"`
+	RotationPeriod     time.Duration `json:"-"`
+	RotationStatements []string      `json:"rotation_statements,omitempty"`
+}
+
+// the fields of DatabaseStaticRole without the custom json methods
+type databaseStaticRoleFields DatabaseStaticRole
+
+// MarshalJSON encodes role in the form expected by vault.
+func (role DatabaseStaticRole) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		databaseStaticRoleFields
+		RotationPeriod string `json:"rotation_period,omitempty"`
+	}{
+		databaseStaticRoleFields: databaseStaticRoleFields(role),
+		RotationPeriod:           ttlString(role.RotationPeriod),
+	})
+}
+
+// UnmarshalJSON decodes role from the form returned by vault.
+func (role *DatabaseStaticRole) UnmarshalJSON(bs []byte) error {
+	response := struct {
+		*databaseStaticRoleFields
+		RotationPeriod int `json:"rotation_period"`
+	}{databaseStaticRoleFields: (*databaseStaticRoleFields)(role)}
+
+	if err := json.Unmarshal(bs, &response); err != nil {
+		return err
+	}
+
+	role.RotationPeriod = time.Duration(response.RotationPeriod) * time.Second
+	return nil
+}
+
+// DatabaseCredentials are dynamic credentials created for a DatabaseRole,
+// which are revoked when their lease expires. The lease may be kept alive
+// by registering it with a LeaseManager.
+type DatabaseCredentials struct {
+	Username  string
+	Password  string
+	LeaseID   string
+	TTL       time.Duration
+	Renewable bool
+}
+
+// StaticCredentials are the current credentials of a DatabaseStaticRole,
+// which remain valid until the password is next rotated.
+type StaticCredentials struct {
+	Username          string
+	Password          string
+	LastVaultRotation time.Time
+	RotationPeriod    time.Duration
+	TTL               time.Duration
+}
+
+type database struct {
+	client *client
+	mount  string
+}
+
+// Database returns a Database for the database secrets engine enabled
+// at mount, which is "database" if mount is empty.
+func (c *client) Database(mount string) Database {
+	mount = strings.Trim(mount, "/")
+	if mount == "" {
+		mount = "database"
+	}
+	return &database{client: c, mount: mount}
+}
+
+func (d *database) path(elements ...string) string {
+	return "/v1/" + d.mount + "/" + strings.Join(elements, "/")
+}
+
+func (d *database) listKeys(path string) ([]string, error) {
+	var data keysData
+	if err := d.client.list(path, &data); err != nil {
+		return nil, err
+	}
+	keys := data.Data["keys"]
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ConfigureConnection creates the connection called name, replacing
+// the connection if it already exists.
+func (d *database) ConfigureConnection(name string, connection DatabaseConnection) error {
+	bs, err := json.Marshal(connection)
+	if err != nil {
+		// do not provide connection anywhere, it may contain the password
+		return errors.Wrapf(err, "failed to create json for database connection %q", name)
+	}
+
+	if err := d.client.post(d.path("config", name), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to configure database connection %q", name)
+	}
+
+	return nil
+}
+
+func (d *database) ReadConnection(name string) (DatabaseConnection, error) {
+	var wrapper struct {
+		Data DatabaseConnection `json:"data"`
+	}
+	if err := d.client.get(d.path("config", name), &wrapper); err != nil {
+		return DatabaseConnection{}, errors.Wrapf(err, "failed to read database connection %q", name)
+	}
+	return wrapper.Data, nil
+}
+
+func (d *database) ListConnections() ([]string, error) {
+	keys, err := d.listKeys(d.path("config"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list database connections")
+	}
+	return keys, nil
+}
+
+func (d *database) DeleteConnection(name string) error {
+	if err := d.client.deleteKey(d.path("config", name)); err != nil {
+		return errors.Wrapf(err, "failed to delete database connection %q", name)
+	}
+	return nil
+}
+
+// ResetConnection closes the connection called name, which vault
+// reopens using its configuration.
+func (d *database) ResetConnection(name string) error {
+	if err := d.client.post(d.path("reset", name), "", nil); err != nil {
+		return errors.Wrapf(err, "failed to reset database connection %q", name)
+	}
+	return nil
+}
+
+// RotateRootCredentials changes the password of the root credentials of
+// the connection called name, after which only vault knows the password.
+func (d *database) RotateRootCredentials(name string) error {
+	if err := d.client.post(d.path("rotate-root", name), "", nil); err != nil {
+		return errors.Wrapf(err, "failed to rotate root credentials of database connection %q", name)
+	}
+	return nil
+}
+
+// CreateRole creates the role called name, replacing the
+// role if it already exists.
+func (d *database) CreateRole(name string, role DatabaseRole) error {
+	bs, err := json.Marshal(role)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for database role %q", name)
+	}
+
+	if err := d.client.post(d.path("roles", name), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to create database role %q", name)
+	}
+
+	return nil
+}
+
+func (d *database) ReadRole(name string) (DatabaseRole, error) {
+	var wrapper struct {
+		Data DatabaseRole `json:"data"`
+	}
+	if err := d.client.get(d.path("roles", name), &wrapper); err != nil {
+		return DatabaseRole{}, errors.Wrapf(err, "failed to read database role %q", name)
+	}
+	return wrapper.Data, nil
+}
+
+func (d *database) ListRoles() ([]string, error) {
+	keys, err := d.listKeys(d.path("roles"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list database roles")
+	}
+	return keys, nil
+}
+
+func (d *database) DeleteRole(name string) error {
+	if err := d.client.deleteKey(d.path("roles", name)); err != nil {
+		return errors.Wrapf(err, "failed to delete database role %q", name)
+	}
+	return nil
+}
+
+type databaseCredentialsWrapper struct {
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+	Data          struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"data"`
+}
+
+// DatabaseCredentials creates new credentials in the database using role.
+func (d *database) DatabaseCredentials(role string) (DatabaseCredentials, error) {
+	var wrapper databaseCredentialsWrapper
+	if err := d.client.get(d.path("creds", role), &wrapper); err != nil {
+		return DatabaseCredentials{}, errors.Wrapf(err, "failed to create database credentials with role %q", role)
+	}
+
+	return DatabaseCredentials{
+		Username:  wrapper.Data.Username,
+		Password:  wrapper.Data.Password,
+		LeaseID:   wrapper.LeaseID,
+		TTL:       time.Duration(wrapper.LeaseDuration) * time.Second,
+		Renewable: wrapper.Renewable,
+	}, nil
+}
+
+// CreateStaticRole creates the static role called name, replacing the
+// role if it already exists. Vault rotates the password of the database
+// user as soon as the role is created.
+func (d *database) CreateStaticRole(name string, role DatabaseStaticRole) error {
+	bs, err := json.Marshal(role)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for database static role %q", name)
+	}
+
+	if err := d.client.post(d.path("static-roles", name), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to create database static role %q", name)
+	}
+
+	return nil
+}
+
+func (d *database) ReadStaticRole(name string) (DatabaseStaticRole, error) {
+	var wrapper struct {
+		Data DatabaseStaticRole `json:"data"`
+	}
+	if err := d.client.get(d.path("static-roles", name), &wrapper); err != nil {
+		return DatabaseStaticRole{}, errors.Wrapf(err, "failed to read database static role %q", name)
+	}
+	return wrapper.Data, nil
+}
+
+func (d *database) ListStaticRoles() ([]string, error) {
+	keys, err := d.listKeys(d.path("static-roles"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list database static roles")
+	}
+	return keys, nil
+}
+
+func (d *database) DeleteStaticRole(name string) error {
+	if err := d.client.deleteKey(d.path("static-roles", name)); err != nil {
+		return errors.Wrapf(err, "failed to delete database static role %q", name)
+	}
+	return nil
+}
+
+type staticCredentialsWrapper struct {
+	Data struct {
+		Username          string    `json:"username"`
+		Password          string    `json:"password"`
+		LastVaultRotation time.Time `json:"last_vault_rotation"`
+		RotationPeriod    int       `json:"rotation_period"`
+		TTL               int       `json:"ttl"`
+	} `json:"data"`
+}
+
+func (d *database) ReadStaticCredentials(name string) (StaticCredentials, error) {
+	var wrapper staticCredentialsWrapper
+	if err := d.client.get(d.path("static-creds", name), &wrapper); err != nil {
+		return StaticCredentials{}, errors.Wrapf(err, "failed to read credentials of database static role %q", name)
+	}
+
+	return StaticCredentials{
+		Username:          wrapper.Data.Username,
+		Password:          wrapper.Data.Password,
+		LastVaultRotation: wrapper.Data.LastVaultRotation,
+		RotationPeriod:    time.Duration(wrapper.Data.RotationPeriod) * time.Second,
+		TTL:               time.Duration(wrapper.Data.TTL) * time.Second,
+	}, nil
+}
+
+// RotateRole immediately rotates the password of the static role called
+// name, rather than waiting for its rotation period to elapse.
+func (d *database) RotateRole(name string) error {
+	if err := d.client.post(d.path("rotate-role", name), "", nil); err != nil {
+		return errors.Wrapf(err, "failed to rotate database static role %q", name)
+	}
+	return nil
+}
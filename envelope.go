@@ -0,0 +1,350 @@
+package vaultapi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// An Envelope encrypts data locally with AES-GCM, using data encryption
+// keys (DEKs) generated by the transit secrets engine. Each DEK is stored
+// alongside the data it encrypts, wrapped by a transit key, so that only
+// the DEK needs to be sent to vault to decrypt the data. Unwrapped DEKs
+// are cached, so most operations require no request to vault at all.
+//
+// More information about transit data keys can be found here:
+// https://www.vaultproject.io/api/secret/transit/index.html#generate-data-key
+type Envelope interface {
+	// Encrypt encrypts plaintext, returning a self-describing
+	// envelope containing the ciphertext and the wrapped DEK.
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt returns the plaintext contained in envelope.
+	Decrypt(envelope []byte) ([]byte, error)
+
+	// Rewrap re-encrypts the DEK contained in envelope with the latest
+	// version of the transit key. This is used to migrate stored envelopes
+	// after the key is rotated. As the wrapped DEK is authenticated along
+	// with the data, the data is sealed again with the same DEK under a
+	// fresh nonce.
+	Rewrap(envelope []byte) ([]byte, error)
+
+	// Purge drops every cached DEK, so that the next encryption
+	// generates a new DEK.
+	Purge()
+}
+
+var (
+	// ErrMalformedEnvelope indicates that data passed to an
+	// Envelope was not produced by an Envelope.
+	ErrMalformedEnvelope = errors.New("malformed envelope")
+)
+
+const (
+	defaultEnvelopeMaxUses   = 1 << 20
+	defaultEnvelopeMaxAge    = 1 * time.Hour
+	defaultEnvelopeCacheSize = 1000
+
+	// random nonces must not be used more
+	// than 2^32 times with the same key
+	maxEnvelopeMaxUses int64 = 1 << 32
+)
+
+// EnvelopeOptions are used to configure an Envelope upon creation.
+type EnvelopeOptions struct {
+	// Key is the name of the transit key used to wrap DEKs.
+	Key string
+
+	// MaxUses is the number of times a DEK may be used before it is
+	// dropped from the cache. This limits how much data is encrypted with
+	// one DEK, as well as how many times a DEK is used to decrypt before it
+	// must be unwrapped by vault again. The default is 1048576.
+	MaxUses int
+
+	// MaxAge is how long a DEK is kept in the cache after it is
+	// generated or unwrapped. The default is one hour.
+	MaxAge time.Duration
+
+	// CacheSize is the maximum number of unwrapped DEKs kept for
+	// decryption. The default is 1000.
+	CacheSize int
+}
+
+type cachedDEK struct {
+	key     []byte
+	created time.Time
+	uses    int
+}
+
+type envelope struct {
+	transit Transit
+	opts    EnvelopeOptions
+	now     func() time.Time
+
+	lock    sync.Mutex
+	current *cachedDEK
+	wrapped string
+	cache   map[string]*cachedDEK
+}
+
+// NewEnvelope creates an Envelope which wraps DEKs with the transit key
+// configured in opts, using transit.
+func NewEnvelope(transit Transit, opts EnvelopeOptions) (Envelope, error) {
+	if opts.Key == "" {
+		return nil, errors.New("envelope requires a transit key")
+	}
+
+	if opts.MaxUses <= 0 {
+		opts.MaxUses = defaultEnvelopeMaxUses
+	}
+
+	if int64(opts.MaxUses) > maxEnvelopeMaxUses {
+		return nil, errors.Errorf("envelope max uses must not exceed %d", maxEnvelopeMaxUses)
+	}
+
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaultEnvelopeMaxAge
+	}
+
+	if opts.CacheSize <= 0 {
+		opts.CacheSize = defaultEnvelopeCacheSize
+	}
+
+	return &envelope{
+		transit: transit,
+		opts:    opts,
+		now:     time.Now,
+		cache:   make(map[string]*cachedDEK),
+	}, nil
+}
+
+// The format of an envelope is
+//
+//	magic | version | length of wrapped DEK | wrapped DEK | nonce | ciphertext
+//
+// where the length is a big endian uint16, and the ciphertext includes the
+// GCM tag. Everything before the nonce is the header, which is authenticated
+// as additional data, so that the wrapped DEK cannot be swapped out.
+var envelopeMagic = []byte("VENV")
+
+const (
+	envelopeVersion = 1
+	envelopeNonce   = 12
+)
+
+type sealedEnvelope struct {
+	wrapped    string
+	nonce      []byte
+	ciphertext []byte
+}
+
+func envelopePrefix() []byte {
+	return append(append([]byte{}, envelopeMagic...), envelopeVersion)
+}
+
+// header returns the part of the envelope before the nonce, which
+// is used as the additional data of the ciphertext
+func (s sealedEnvelope) header() []byte {
+	var buf bytes.Buffer
+	buf.Write(envelopePrefix())
+	_ = binary.Write(&buf, binary.BigEndian, uint16(len(s.wrapped)))
+	buf.WriteString(s.wrapped)
+	return buf.Bytes()
+}
+
+func (s sealedEnvelope) marshal() []byte {
+	var buf bytes.Buffer
+	buf.Write(s.header())
+	buf.Write(s.nonce)
+	buf.Write(s.ciphertext)
+	return buf.Bytes()
+}
+
+func unmarshalEnvelope(bs []byte) (sealedEnvelope, error) {
+	prefix := envelopePrefix()
+	if len(bs) < len(prefix)+2 || !bytes.Equal(bs[:len(prefix)], prefix) {
+		return sealedEnvelope{}, ErrMalformedEnvelope
+	}
+	bs = bs[len(prefix):]
+
+	length := int(binary.BigEndian.Uint16(bs))
+	bs = bs[2:]
+	if len(bs) < length+envelopeNonce {
+		return sealedEnvelope{}, ErrMalformedEnvelope
+	}
+
+	return sealedEnvelope{
+		wrapped:    string(bs[:length]),
+		nonce:      bs[length : length+envelopeNonce],
+		ciphertext: bs[length+envelopeNonce:],
+	}, nil
+}
+
+// usable returns whether dek is still within the limits of use and age
+func (e *envelope) usable(dek *cachedDEK) bool {
+	return dek.uses < e.opts.MaxUses && e.now().Sub(dek.created) < e.opts.MaxAge
+}
+
+// encryptionKey returns the current DEK, generating a new one if the
+// current one has reached its limits
+func (e *envelope) encryptionKey() ([]byte, string, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.current == nil || !e.usable(e.current) {
+		dataKey, err := e.transit.GenerateDataKey(e.opts.Key, 256, true)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to generate envelope data key")
+		}
+		e.current = &cachedDEK{key: dataKey.Plaintext, created: e.now()}
+		e.wrapped = dataKey.Ciphertext
+
+		// data encrypted with the new DEK can be decrypted without vault
+		e.remember(dataKey.Ciphertext, &cachedDEK{key: dataKey.Plaintext, created: e.now()})
+	}
+
+	e.current.uses++
+	return e.current.key, e.wrapped, nil
+}
+
+// decryptionKey returns the unwrapped DEK for wrapped, from the cache
+// if possible, otherwise by unwrapping it with vault
+func (e *envelope) decryptionKey(wrapped string) ([]byte, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if dek, exists := e.cache[wrapped]; exists && e.usable(dek) {
+		dek.uses++
+		return dek.key, nil
+	}
+
+	key, err := e.transit.Decrypt(e.opts.Key, wrapped)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unwrap envelope data key")
+	}
+
+	e.remember(wrapped, &cachedDEK{key: key, created: e.now(), uses: 1})
+	return key, nil
+}
+
+// remember caches dek, evicting expired keys and then the oldest
+// key if the cache is full
+func (e *envelope) remember(wrapped string, dek *cachedDEK) {
+	for w, cached := range e.cache {
+		if !e.usable(cached) {
+			delete(e.cache, w)
+		}
+	}
+
+	for len(e.cache) >= e.opts.CacheSize {
+		var oldest string
+		for w, cached := range e.cache {
+			if oldest == "" || cached.created.Before(e.cache[oldest].created) {
+				oldest = w
+			}
+		}
+		delete(e.cache, oldest)
+	}
+
+	e.cache[wrapped] = dek
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with key under a fresh nonce, authenticating
+// the header of an envelope containing wrapped
+func seal(key []byte, wrapped string, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create envelope cipher")
+	}
+
+	nonce := make([]byte, envelopeNonce)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to create envelope nonce")
+	}
+
+	sealed := sealedEnvelope{
+		wrapped: wrapped,
+		nonce:   nonce,
+	}
+	sealed.ciphertext = gcm.Seal(nil, nonce, plaintext, sealed.header())
+
+	return sealed.marshal(), nil
+}
+
+// open decrypts the envelope bs, returning the plaintext
+// along with the DEK which was used to encrypt it
+func (e *envelope) open(bs []byte) ([]byte, sealedEnvelope, []byte, error) {
+	sealed, err := unmarshalEnvelope(bs)
+	if err != nil {
+		return nil, sealedEnvelope{}, nil, err
+	}
+
+	key, err := e.decryptionKey(sealed.wrapped)
+	if err != nil {
+		return nil, sealedEnvelope{}, nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, sealedEnvelope{}, nil, errors.Wrap(err, "failed to create envelope cipher")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed.nonce, sealed.ciphertext, sealed.header())
+	if err != nil {
+		return nil, sealedEnvelope{}, nil, errors.Wrap(err, "failed to decrypt envelope")
+	}
+
+	return plaintext, sealed, key, nil
+}
+
+func (e *envelope) Encrypt(plaintext []byte) ([]byte, error) {
+	key, wrapped, err := e.encryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return seal(key, wrapped, plaintext)
+}
+
+func (e *envelope) Decrypt(bs []byte) ([]byte, error) {
+	plaintext, _, _, err := e.open(bs)
+	return plaintext, err
+}
+
+func (e *envelope) Rewrap(bs []byte) ([]byte, error) {
+	plaintext, sealed, key, err := e.open(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	rewrapped, err := e.transit.Rewrap(e.opts.Key, sealed.wrapped)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to rewrap envelope data key")
+	}
+
+	return seal(key, rewrapped, plaintext)
+}
+
+func (e *envelope) Purge() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.current = nil
+	e.wrapped = ""
+	e.cache = make(map[string]*cachedDEK)
+}
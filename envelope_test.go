@@ -0,0 +1,236 @@
+package vaultapi
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDataKeys generates and unwraps data keys the way transit would,
+// counting the requests made
+type fakeDataKeys struct {
+	Transit // not implemented
+
+	version   int
+	keys      map[string][]byte
+	generated int
+	unwrapped int
+}
+
+func newFakeDataKeys() *fakeDataKeys {
+	return &fakeDataKeys{version: 1, keys: make(map[string][]byte)}
+}
+
+func (f *fakeDataKeys) wrap(key []byte) string {
+	nonce := make([]byte, 8)
+	_, _ = rand.Read(nonce)
+	wrapped := fmt.Sprintf("vault:v%d:%s", f.version, base64.StdEncoding.EncodeToString(nonce))
+	f.keys[wrapped] = key
+	return wrapped
+}
+
+func (f *fakeDataKeys) GenerateDataKey(key string, bits int, plaintext bool, _ ...TransitOption) (TransitDataKey, error) {
+	f.generated++
+	dek := make([]byte, bits/8)
+	_, _ = rand.Read(dek)
+	return TransitDataKey{Plaintext: dek, Ciphertext: f.wrap(dek), KeyVersion: f.version}, nil
+}
+
+func (f *fakeDataKeys) Decrypt(key, ciphertext string, _ ...TransitOption) ([]byte, error) {
+	f.unwrapped++
+	dek, exists := f.keys[ciphertext]
+	if !exists {
+		return nil, errors.New("unknown ciphertext")
+	}
+	return dek, nil
+}
+
+func (f *fakeDataKeys) Rewrap(key, ciphertext string, _ ...TransitOption) (string, error) {
+	dek, exists := f.keys[ciphertext]
+	if !exists {
+		return "", errors.New("unknown ciphertext")
+	}
+	return f.wrap(dek), nil
+}
+
+func Test_Envelope_roundTrip(t *testing.T) {
+	fake := newFakeDataKeys()
+	env, err := NewEnvelope(fake, EnvelopeOptions{Key: "dek"})
+	require.NoError(t, err)
+
+	sealed1, err := env.Encrypt([]byte("record one"))
+	require.NoError(t, err)
+	sealed2, err := env.Encrypt([]byte("record two"))
+	require.NoError(t, err)
+	require.Equal(t, 1, fake.generated)
+	require.True(t, bytes.HasPrefix(sealed1, []byte("VENV")))
+	require.False(t, bytes.Contains(sealed1, []byte("record one")))
+
+	plaintext, err := env.Decrypt(sealed1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("record one"), plaintext)
+
+	plaintext, err = env.Decrypt(sealed2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("record two"), plaintext)
+
+	// the DEK used for encryption is already cached
+	require.Equal(t, 0, fake.unwrapped)
+
+	env.Purge()
+	plaintext, err = env.Decrypt(sealed1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("record one"), plaintext)
+	require.Equal(t, 1, fake.unwrapped)
+}
+
+func Test_Envelope_limits(t *testing.T) {
+	fake := newFakeDataKeys()
+	env, err := NewEnvelope(fake, EnvelopeOptions{Key: "dek", MaxUses: 2, MaxAge: time.Minute})
+	require.NoError(t, err)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	env.(*envelope).now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		_, err := env.Encrypt([]byte("data"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, fake.generated)
+
+	sealed, err := env.Encrypt([]byte("data"))
+	require.NoError(t, err)
+	require.Equal(t, 3, fake.generated)
+
+	now = now.Add(time.Minute)
+	_, err = env.Encrypt([]byte("data"))
+	require.NoError(t, err)
+	require.Equal(t, 4, fake.generated)
+
+	// the cached DEK has expired, so it must be unwrapped again
+	_, err = env.Decrypt(sealed)
+	require.NoError(t, err)
+	require.Equal(t, 1, fake.unwrapped)
+
+	_, err = env.Decrypt(sealed)
+	require.NoError(t, err)
+	_, err = env.Decrypt(sealed)
+	require.NoError(t, err)
+	require.Equal(t, 2, fake.unwrapped)
+}
+
+func Test_Envelope_cacheSize(t *testing.T) {
+	fake := newFakeDataKeys()
+	env, err := NewEnvelope(fake, EnvelopeOptions{Key: "dek", MaxUses: 1, CacheSize: 2})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err := env.Encrypt([]byte("data"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 10, fake.generated)
+	require.Equal(t, 2, len(env.(*envelope).cache))
+}
+
+func Test_Envelope_Rewrap(t *testing.T) {
+	fake := newFakeDataKeys()
+	env, err := NewEnvelope(fake, EnvelopeOptions{Key: "dek"})
+	require.NoError(t, err)
+
+	sealed, err := env.Encrypt([]byte("long lived"))
+	require.NoError(t, err)
+
+	fake.version = 2
+	rewrapped, err := env.Rewrap(sealed)
+	require.NoError(t, err)
+	require.True(t, bytes.Contains(rewrapped, []byte("vault:v2:")))
+
+	// the data is sealed again under a fresh nonce
+	before, err := unmarshalEnvelope(sealed)
+	require.NoError(t, err)
+	after, err := unmarshalEnvelope(rewrapped)
+	require.NoError(t, err)
+	require.NotEqual(t, before.nonce, after.nonce)
+
+	env.Purge()
+	plaintext, err := env.Decrypt(rewrapped)
+	require.NoError(t, err)
+	require.Equal(t, []byte("long lived"), plaintext)
+}
+
+func Test_Envelope_swappedHeader(t *testing.T) {
+	fake := newFakeDataKeys()
+	env, err := NewEnvelope(fake, EnvelopeOptions{Key: "dek"})
+	require.NoError(t, err)
+
+	sealed, err := env.Encrypt([]byte("data"))
+	require.NoError(t, err)
+	rewrapped, err := env.Rewrap(sealed)
+	require.NoError(t, err)
+
+	// both wrapped DEKs unwrap to the same DEK, yet the
+	// header of one does not authenticate the other
+	original, err := unmarshalEnvelope(sealed)
+	require.NoError(t, err)
+	other, err := unmarshalEnvelope(rewrapped)
+	require.NoError(t, err)
+	original.wrapped = other.wrapped
+
+	_, err = env.Decrypt(original.marshal())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to decrypt envelope")
+}
+
+func Test_Envelope_malformed(t *testing.T) {
+	env, err := NewEnvelope(newFakeDataKeys(), EnvelopeOptions{Key: "dek"})
+	require.NoError(t, err)
+
+	sealed, err := env.Encrypt([]byte("data"))
+	require.NoError(t, err)
+
+	for _, bs := range [][]byte{nil, []byte("VENV"), []byte("XENV\x01\x00\x00"), sealed[:20]} {
+		_, err := env.Decrypt(bs)
+		require.Equal(t, ErrMalformedEnvelope, err)
+	}
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = env.Decrypt(tampered)
+	require.Error(t, err)
+
+	_, err = NewEnvelope(newFakeDataKeys(), EnvelopeOptions{})
+	require.Error(t, err)
+}
+
+func Test_Envelope_DevVault(t *testing.T) {
+	transit := getTransit(t)
+
+	err := transit.CreateKey("test-envelope", TransitKeyOptions{})
+	require.NoError(t, err)
+	defer deleteTransitKey(t, transit, "test-envelope")
+
+	env, err := NewEnvelope(transit, EnvelopeOptions{Key: "test-envelope"})
+	require.NoError(t, err)
+
+	sealed, err := env.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+
+	require.NoError(t, transit.RotateKey("test-envelope"))
+
+	rewrapped, err := env.Rewrap(sealed)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(rewrapped), "vault:v2:"))
+
+	env.Purge()
+	plaintext, err := env.Decrypt(rewrapped)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), plaintext)
+}
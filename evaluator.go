@@ -0,0 +1,254 @@
+package vaultapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A PolicyEvaluator computes the capabilities a set of policies grants on
+// a path, without making any requests to vault. It follows the same rules
+// as vault for matching * globs and + segments, for merging rules of the
+// same path across policies, and for choosing between rules when more than
+// one matches.
+//
+// More information about how vault chooses a rule can be found here:
+// https://www.vaultproject.io/docs/concepts/policies.html#priority-matching
+type PolicyEvaluator interface {
+	// Capabilities returns the capabilities granted on path, which are
+	// ["deny"] if no rule matches path, or ["root"] if the root policy
+	// is among the policies being evaluated.
+	Capabilities(path string) []string
+
+	// Allowed returns whether capability is granted on path.
+	Allowed(path, capability string) bool
+
+	// Explain returns the capabilities granted on path along with
+	// the rule and policies from which they are granted.
+	Explain(path string) PolicyDecision
+}
+
+// A PolicyDecision describes how the capabilities on a path were
+// determined by a PolicyEvaluator.
+type PolicyDecision struct {
+	// Path is the path being evaluated.
+	Path string
+
+	// Capabilities are the capabilities granted on Path.
+	Capabilities []string
+
+	// Rule is the path of the rule which determined the capabilities,
+	// or empty if no rule matched Path.
+	Rule string
+
+	// Policies are the names of the policies which contain Rule.
+	Policies []string
+
+	// Shadowed are the paths of rules which also matched Path,
+	// but were of lower priority than Rule.
+	Shadowed []string
+}
+
+// the rules of every policy with the same path are merged into one
+type mergedRule struct {
+	path         string
+	capabilities map[string]bool
+	policies     []string
+}
+
+type policyEvaluator struct {
+	root  []string
+	rules []*mergedRule
+}
+
+// NewPolicyEvaluator creates a PolicyEvaluator for a token which
+// has been assigned policies.
+func NewPolicyEvaluator(policies ...Policy) (PolicyEvaluator, error) {
+	e := &policyEvaluator{}
+	byPath := make(map[string]*mergedRule)
+
+	for _, policy := range policies {
+		if policy.Name == "root" {
+			e.root = append(e.root, policy.Name)
+			continue
+		}
+
+		if err := policy.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate policy %q", policy.Name)
+		}
+
+		for _, rule := range policy.Paths {
+			path := strings.TrimPrefix(rule.Path, "/")
+			merged, exists := byPath[path]
+			if !exists {
+				merged = &mergedRule{path: path, capabilities: make(map[string]bool)}
+				byPath[path] = merged
+				e.rules = append(e.rules, merged)
+			}
+			for _, capability := range rule.EffectiveCapabilities() {
+				merged.capabilities[capability] = true
+			}
+			if !containsString(merged.policies, policy.Name) {
+				merged.policies = append(merged.policies, policy.Name)
+			}
+		}
+	}
+
+	return e, nil
+}
+
+func containsString(strs []string, s string) bool {
+	for _, each := range strs {
+		if each == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *policyEvaluator) Capabilities(path string) []string {
+	return e.Explain(path).Capabilities
+}
+
+func (e *policyEvaluator) Allowed(path, capability string) bool {
+	for _, granted := range e.Capabilities(path) {
+		if granted == "root" || granted == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *policyEvaluator) Explain(path string) PolicyDecision {
+	path = strings.TrimPrefix(path, "/")
+	decision := PolicyDecision{Path: path}
+
+	if len(e.root) > 0 {
+		decision.Capabilities = []string{"root"}
+		decision.Policies = e.root
+		return decision
+	}
+
+	var matches []*mergedRule
+	for _, rule := range e.rules {
+		if matchPolicyPath(rule.path, path) {
+			matches = append(matches, rule)
+		}
+	}
+
+	if len(matches) == 0 {
+		decision.Capabilities = []string{CapabilityDeny}
+		return decision
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return lowerPriority(matches[j].path, matches[i].path)
+	})
+
+	best := matches[0]
+	decision.Rule = best.path
+	decision.Policies = append([]string(nil), best.policies...)
+	sort.Strings(decision.Policies)
+	for _, shadowed := range matches[1:] {
+		decision.Shadowed = append(decision.Shadowed, shadowed.path)
+	}
+
+	// deny takes precedence over every other capability
+	if best.capabilities[CapabilityDeny] {
+		decision.Capabilities = []string{CapabilityDeny}
+		return decision
+	}
+
+	for capability := range best.capabilities {
+		decision.Capabilities = append(decision.Capabilities, capability)
+	}
+	sort.Strings(decision.Capabilities)
+
+	return decision
+}
+
+// matchPolicyPath returns whether the rule path pattern matches path,
+// where pattern may end in a * glob and contain + segments
+func matchPolicyPath(pattern, path string) bool {
+	glob := strings.HasSuffix(pattern, "*")
+	pattern = strings.TrimSuffix(pattern, "*")
+
+	if !strings.Contains(pattern, "+") {
+		if glob {
+			return strings.HasPrefix(path, pattern)
+		}
+		return path == pattern
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+
+	if len(pathSegments) < len(patternSegments) {
+		return false
+	}
+
+	if !glob && len(pathSegments) != len(patternSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		last := i == len(patternSegments)-1
+		switch {
+		case segment == "+":
+			// matches any one segment
+		case last && glob:
+			if !strings.HasPrefix(pathSegments[i], segment) {
+				return false
+			}
+		case segment != pathSegments[i]:
+			return false
+		}
+	}
+
+	return true
+}
+
+// lowerPriority returns whether the rule path p1 is of lower priority than
+// the rule path p2, when both match the same path
+func lowerPriority(p1, p2 string) bool {
+	// the first wildcard occurring earlier has lower priority
+	if w1, w2 := firstWildcard(p1), firstWildcard(p2); w1 != w2 {
+		return w1 < w2
+	}
+
+	// ending in a glob has lower priority
+	if g1, g2 := strings.HasSuffix(p1, "*"), strings.HasSuffix(p2, "*"); g1 != g2 {
+		return g1
+	}
+
+	// more + segments has lower priority
+	if s1, s2 := plusSegments(p1), plusSegments(p2); s1 != s2 {
+		return s1 > s2
+	}
+
+	// shorter has lower priority
+	if len(p1) != len(p2) {
+		return len(p1) < len(p2)
+	}
+
+	// lexicographically smaller has lower priority
+	return p1 < p2
+}
+
+func firstWildcard(path string) int {
+	if index := strings.IndexAny(path, "+*"); index >= 0 {
+		return index
+	}
+	return len(path)
+}
+
+func plusSegments(path string) int {
+	count := 0
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "+" {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,119 @@
+package vaultapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parsePolicy(t *testing.T, name, document string) Policy {
+	policy, err := ParsePolicy(document)
+	require.NoError(t, err)
+	policy.Name = name
+	return policy
+}
+
+func Test_matchPolicyPath(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"secret/foo", "secret/foo", true},
+		{"secret/foo", "secret/foo/bar", false},
+		{"secret/foo*", "secret/foo", true},
+		{"secret/foo*", "secret/foobar/baz", true},
+		{"secret/foo/*", "secret/foo", false},
+		{"secret/+/bar", "secret/foo/bar", true},
+		{"secret/+/bar", "secret/foo/baz", false},
+		{"secret/+/bar", "secret/foo/bar/baz", false},
+		{"secret/+/bar*", "secret/foo/barn/baz", true},
+		{"secret/+/+", "secret/foo/bar", true},
+		{"secret/+/+", "secret/foo", false},
+		{"secret/+/*", "secret/foo/", true},
+		{"secret/+/*", "secret/foo", false},
+		{"*", "anything/at/all", true},
+	} {
+		require.Equal(t, tc.match, matchPolicyPath(tc.pattern, tc.path), "pattern: %s, path: %s", tc.pattern, tc.path)
+	}
+}
+
+func Test_lowerPriority(t *testing.T) {
+	for _, tc := range []struct {
+		lower  string
+		higher string
+	}{
+		{"secret/*", "secret/foo"},
+		{"secret/+/bar", "secret/foo/+"},
+		{"secret/foo*", "secret/foo"},
+		{"secret/+/+", "secret/+/bar"},
+		{"secret/foo*", "secret/foo/*"},
+		{"secret/a", "secret/b"},
+	} {
+		require.True(t, lowerPriority(tc.lower, tc.higher), "lower: %s, higher: %s", tc.lower, tc.higher)
+		require.False(t, lowerPriority(tc.higher, tc.lower), "lower: %s, higher: %s", tc.lower, tc.higher)
+	}
+}
+
+func Test_PolicyEvaluator(t *testing.T) {
+	team := parsePolicy(t, "team", `
+path "secret/*" {
+  capabilities = ["read", "list"]
+}
+
+path "secret/+/config" {
+  capabilities = ["read", "update"]
+}
+
+path "secret/admin/*" {
+  capabilities = ["deny"]
+}`)
+
+	ops := parsePolicy(t, "ops", `
+path "secret/*" {
+  capabilities = ["create"]
+}
+
+path "sys/leases/*" {
+  policy = "sudo"
+}`)
+
+	evaluator, err := NewPolicyEvaluator(team, ops)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"create", "list", "read"}, evaluator.Capabilities("secret/foo"))
+	require.Equal(t, []string{"read", "update"}, evaluator.Capabilities("/secret/app/config"))
+	require.Equal(t, []string{"deny"}, evaluator.Capabilities("secret/admin/keys"))
+	require.Equal(t, []string{"deny"}, evaluator.Capabilities("auth/token/create"))
+	require.True(t, evaluator.Allowed("sys/leases/revoke", "sudo"))
+	require.False(t, evaluator.Allowed("secret/foo", "delete"))
+
+	decision := evaluator.Explain("secret/app/config")
+	require.Equal(t, "secret/+/config", decision.Rule)
+	require.Equal(t, []string{"team"}, decision.Policies)
+	require.Equal(t, []string{"secret/*"}, decision.Shadowed)
+
+	decision = evaluator.Explain("secret/foo")
+	require.Equal(t, "secret/*", decision.Rule)
+	require.Equal(t, []string{"ops", "team"}, decision.Policies)
+	require.Empty(t, decision.Shadowed)
+
+	decision = evaluator.Explain("auth/token/create")
+	require.Equal(t, "", decision.Rule)
+	require.Empty(t, decision.Policies)
+}
+
+func Test_PolicyEvaluator_root(t *testing.T) {
+	evaluator, err := NewPolicyEvaluator(Policy{Name: "root"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"root"}, evaluator.Capabilities("sys/seal"))
+	require.True(t, evaluator.Allowed("sys/seal", "sudo"))
+}
+
+func Test_PolicyEvaluator_invalid(t *testing.T) {
+	_, err := NewPolicyEvaluator(Policy{
+		Name:  "broken",
+		Paths: []PathRule{{Path: "secret/*/foo", Capabilities: []string{"read"}}},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,213 @@
+package vaultapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A GenerateRootStatus is returned upon requesting the progress of a root
+// token generation, and after providing each key share. Once the threshold
+// of key shares have been provided, Complete is set and EncodedToken
+// contains the new root token, encoded using the OTP.
+//
+// More information about generating root tokens can be found here:
+// https://www.vaultproject.io/api/system/generate-root.html
+type GenerateRootStatus struct {
+	Started        bool   `json:"started"`
+	Nonce          string `json:"nonce"`
+	Progress       int    `json:"progress"`
+	Required       int    `json:"required"`
+	Complete       bool   `json:"complete"`
+	EncodedToken   string `json:"encoded_token"`
+	PGPFingerprint string `json:"pgp_fingerprint"`
+	OTP            string `json:"otp"`
+	OTPLength      int    `json:"otp_length"`
+}
+
+func (c *client) GenerateRootStatus() (GenerateRootStatus, error) {
+	var status GenerateRootStatus
+	if err := c.get("/v1/sys/generate-root/attempt", &status); err != nil {
+		return GenerateRootStatus{}, errors.Wrap(err, "failed to read generate root status")
+	}
+	return status, nil
+}
+
+func (c *client) GenerateRootInit(otp string) (GenerateRootStatus, error) {
+	bs, err := json.Marshal(struct {
+		OTP string `json:"otp,omitempty"`
+	}{OTP: otp})
+	if err != nil {
+		return GenerateRootStatus{}, err
+	}
+
+	var status GenerateRootStatus
+	if err := c.post("/v1/sys/generate-root/attempt", string(bs), &status); err != nil {
+		return GenerateRootStatus{}, errors.Wrap(err, "failed to start generate root")
+	}
+
+	return status, nil
+}
+
+func (c *client) GenerateRootUpdate(key, nonce string) (GenerateRootStatus, error) {
+	bs, err := json.Marshal(keyShare{Key: key, Nonce: nonce})
+	if err != nil {
+		// do not provide key anywhere
+		return GenerateRootStatus{}, errors.New("failed to create json for generate root update")
+	}
+
+	var status GenerateRootStatus
+	if err := c.post("/v1/sys/generate-root/update", string(bs), &status); err != nil {
+		// do not provide key anywhere
+		return GenerateRootStatus{}, errors.Wrapf(err, "failed to provide key share for generate root %q", nonce)
+	}
+
+	return status, nil
+}
+
+func (c *client) GenerateRootCancel() error {
+	if err := c.delete("/v1/sys/generate-root/attempt"); err != nil {
+		return errors.Wrap(err, "failed to cancel generate root")
+	}
+	return nil
+}
+
+// A GenerateRootProcess keeps track of an in-progress root token generation,
+// including the OTP used to decode the resulting root token.
+type GenerateRootProcess struct {
+	sys    Sys
+	otp    string
+	status GenerateRootStatus
+}
+
+// StartGenerateRoot begins a new root token generation, using a randomly
+// generated OTP which is kept by the returned GenerateRootProcess.
+func StartGenerateRoot(sys Sys) (*GenerateRootProcess, error) {
+	current, err := sys.GenerateRootStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := GenerateOTP(current.OTPLength)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := sys.GenerateRootInit(otp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenerateRootProcess{sys: sys, otp: otp, status: status}, nil
+}
+
+// Status returns the most recently known status of the root token generation.
+func (g *GenerateRootProcess) Status() GenerateRootStatus {
+	return g.status
+}
+
+// Submit provides one key share towards the root token generation.
+func (g *GenerateRootProcess) Submit(key string) (GenerateRootStatus, error) {
+	if g.status.Complete {
+		return g.status, errors.New("generate root is already complete")
+	}
+
+	status, err := g.sys.GenerateRootUpdate(key, g.status.Nonce)
+	if err != nil {
+		return g.status, err
+	}
+
+	if status.Nonce == "" {
+		status.Nonce = g.status.Nonce
+	}
+	g.status = status
+
+	return status, nil
+}
+
+// Token returns the decoded root token, once the root
+// token generation is complete.
+func (g *GenerateRootProcess) Token() (string, error) {
+	if !g.status.Complete {
+		return "", errors.New("generate root is not complete")
+	}
+	return DecodeRootToken(g.status.EncodedToken, g.otp)
+}
+
+// Cancel aborts the root token generation, discarding any
+// key shares provided so far.
+func (g *GenerateRootProcess) Cancel() error {
+	return g.sys.GenerateRootCancel()
+}
+
+const otpCharacters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// GenerateOTP creates a random OTP suitable for generating a root token. The
+// length should be the OTPLength reported by GenerateRootStatus. Versions of
+// vault before 1.0 report a length of 0, and instead expect a base64 encoded
+// 16 byte value, which is what is generated in that case.
+func GenerateOTP(length int) (string, error) {
+	if length <= 0 {
+		bs := make([]byte, 16)
+		if _, err := rand.Read(bs); err != nil {
+			return "", errors.Wrap(err, "failed to generate otp")
+		}
+		return base64.StdEncoding.EncodeToString(bs), nil
+	}
+
+	max := big.NewInt(int64(len(otpCharacters)))
+	otp := make([]byte, length)
+	for i := range otp {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to generate otp")
+		}
+		otp[i] = otpCharacters[n.Int64()]
+	}
+	return string(otp), nil
+}
+
+// DecodeRootToken decodes the encoded root token produced by a completed
+// root token generation, using the OTP provided when the generation was
+// started.
+func DecodeRootToken(encoded, otp string) (string, error) {
+	// versions of vault before 1.0 used a base64 encoded 16 byte otp,
+	// with the resulting token in the form of a uuid
+	if otpBytes, err := base64.StdEncoding.DecodeString(otp); err == nil && len(otpBytes) == 16 {
+		tokenBytes, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to decode root token")
+		}
+		token, err := xorBytes(tokenBytes, otpBytes)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%x-%x-%x-%x-%x", token[0:4], token[4:6], token[6:8], token[8:10], token[10:]), nil
+	}
+
+	tokenBytes, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode root token")
+	}
+	token, err := xorBytes(tokenBytes, []byte(otp))
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+func xorBytes(a, b []byte) ([]byte, error) {
+	if len(a) != len(b) {
+		return nil, errors.Errorf("length of otp (%d) does not match length of encoded token (%d)", len(b), len(a))
+	}
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+	return result, nil
+}
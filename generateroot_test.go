@@ -0,0 +1,79 @@
+package vaultapi
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateRoot_StartCancel(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	generate, err := StartGenerateRoot(client)
+	require.NoError(t, err)
+	require.True(t, generate.Status().Started)
+	require.NotEmpty(t, generate.Status().Nonce)
+
+	_, err = generate.Token()
+	require.Error(t, err)
+
+	require.NoError(t, generate.Cancel())
+
+	status, err := client.GenerateRootStatus()
+	require.NoError(t, err)
+	require.False(t, status.Started)
+}
+
+func Test_GenerateOTP(t *testing.T) {
+	otp, err := GenerateOTP(24)
+	require.NoError(t, err)
+	require.Len(t, otp, 24)
+
+	legacy, err := GenerateOTP(0)
+	require.NoError(t, err)
+	bs, err := base64.StdEncoding.DecodeString(legacy)
+	require.NoError(t, err)
+	require.Len(t, bs, 16)
+}
+
+func Test_DecodeRootToken(t *testing.T) {
+	token := "s.Kh3o0w9e8Xp2rJ5dLq7mN1vB"
+	otp, err := GenerateOTP(len(token))
+	require.NoError(t, err)
+
+	encoded := make([]byte, len(token))
+	for i := range encoded {
+		encoded[i] = token[i] ^ otp[i]
+	}
+
+	decoded, err := DecodeRootToken(base64.RawStdEncoding.EncodeToString(encoded), otp)
+	require.NoError(t, err)
+	require.Equal(t, token, decoded)
+
+	_, err = DecodeRootToken(base64.RawStdEncoding.EncodeToString(encoded), otp[1:])
+	require.Error(t, err)
+}
+
+func Test_DecodeRootToken_Legacy(t *testing.T) {
+	uuid := []byte{
+		0x6d, 0x3a, 0x1f, 0x52, 0x0c, 0x4e, 0x4b, 0x8a,
+		0x91, 0x2f, 0x5e, 0x13, 0xc7, 0x08, 0x44, 0xaa,
+	}
+	otp := []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+	}
+
+	encoded := make([]byte, len(uuid))
+	for i := range encoded {
+		encoded[i] = uuid[i] ^ otp[i]
+	}
+
+	decoded, err := DecodeRootToken(
+		base64.StdEncoding.EncodeToString(encoded),
+		base64.StdEncoding.EncodeToString(otp),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "6d3a1f52-0c4e-4b8a-912f-5e13c70844aa", decoded)
+}
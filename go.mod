@@ -1,6 +1,7 @@
 module github.com/shoenig/vaultapi
 
 require (
+	github.com/hashicorp/hcl v1.0.0
 	github.com/pkg/errors v0.8.1
 	github.com/shoenig/mockery3/v3 v3.1.1
 	github.com/stretchr/objx v0.2.0 // indirect
@@ -8,4 +9,4 @@ require (
 	gophers.dev/pkgs/ignore v0.2.0
 )
 
-go 1.12
+go 1.14
@@ -0,0 +1,221 @@
+package vaultapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	hclparser "github.com/hashicorp/hcl/hcl/parser"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/pkg/errors"
+)
+
+// Policy documents are parsed into an ordered tree of items, rather than
+// decoded directly into structs, so that the order of path rules is kept
+// and so that duplicate keys can be detected. The HCL form is parsed with
+// the HCL library used by vault, and the JSON form with encoding/json.
+
+// An hclItem is one assignment or block within an hclObject. Blocks
+// may have labels, e.g. the path of a path block in a policy.
+type hclItem struct {
+	key    string
+	labels []string
+	value  interface{}
+	line   int
+}
+
+// An hclObject preserves the order of its items, which
+// may contain duplicate keys.
+type hclObject []hclItem
+
+// parseDocument parses input as JSON if it is a JSON object,
+// and as HCL otherwise.
+func parseDocument(input string) (hclObject, error) {
+	if strings.HasPrefix(strings.TrimSpace(input), "{") {
+		return parseJSON(input)
+	}
+	return parseHCL(input)
+}
+
+func parseHCL(input string) (hclObject, error) {
+	file, err := hclparser.Parse([]byte(input))
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := file.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, errors.New("document must be a list of items")
+	}
+
+	return hclObjectList(list)
+}
+
+func hclObjectList(list *ast.ObjectList) (hclObject, error) {
+	object := make(hclObject, 0, len(list.Items))
+	for _, node := range list.Items {
+		item := hclItem{
+			key:  hclKey(node.Keys[0]),
+			line: node.Pos().Line,
+		}
+		for _, label := range node.Keys[1:] {
+			item.labels = append(item.labels, hclKey(label))
+		}
+
+		value, err := hclValue(node.Val)
+		if err != nil {
+			return nil, err
+		}
+		item.value = value
+
+		object = append(object, item)
+	}
+	return object, nil
+}
+
+func hclKey(key *ast.ObjectKey) string {
+	if s, ok := key.Token.Value().(string); ok {
+		return s
+	}
+	return key.Token.Text
+}
+
+func hclValue(node ast.Node) (interface{}, error) {
+	switch node := node.(type) {
+	case *ast.LiteralType:
+		switch node.Token.Type {
+		case token.NUMBER:
+			i, err := strconv.Atoi(node.Token.Text)
+			if err != nil {
+				return nil, errors.Errorf("line %d: invalid number %s", node.Pos().Line, node.Token.Text)
+			}
+			return i, nil
+		case token.FLOAT:
+			f, err := strconv.ParseFloat(node.Token.Text, 64)
+			if err != nil {
+				return nil, errors.Errorf("line %d: invalid number %s", node.Pos().Line, node.Token.Text)
+			}
+			return f, nil
+		}
+		return node.Token.Value(), nil
+	case *ast.ListType:
+		list := make([]interface{}, 0, len(node.List))
+		for _, element := range node.List {
+			value, err := hclValue(element)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case *ast.ObjectType:
+		return hclObjectList(node.List)
+	}
+	return nil, errors.Errorf("line %d: unsupported value", node.Pos().Line)
+}
+
+type jsonParser struct {
+	input   string
+	decoder *json.Decoder
+}
+
+func parseJSON(input string) (hclObject, error) {
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.UseNumber()
+	p := &jsonParser{input: input, decoder: decoder}
+
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := value.(hclObject)
+	if !ok {
+		return nil, errors.New("document must be a JSON object")
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.Errorf("line %d: unexpected data after JSON object", p.line())
+	}
+
+	return object, nil
+}
+
+// line returns the line of input the decoder has reached
+func (p *jsonParser) line() int {
+	offset := int(p.decoder.InputOffset())
+	return bytes.Count([]byte(p.input[:offset]), []byte("\n")) + 1
+}
+
+func (p *jsonParser) value() (interface{}, error) {
+	token, err := p.decoder.Token()
+	if err != nil {
+		return nil, errors.Wrapf(err, "line %d: invalid JSON", p.line())
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			return p.object()
+		case '[':
+			return p.list()
+		}
+		return nil, errors.Errorf("line %d: unexpected %s", p.line(), token)
+	case json.Number:
+		if i, err := token.Int64(); err == nil {
+			return int(i), nil
+		}
+		return token.Float64()
+	}
+
+	// string, bool, or nil
+	return token, nil
+}
+
+func (p *jsonParser) object() (hclObject, error) {
+	object := hclObject{}
+	for p.decoder.More() {
+		token, err := p.decoder.Token()
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: invalid JSON", p.line())
+		}
+		line := p.line()
+		key, ok := token.(string)
+		if !ok {
+			return nil, errors.Errorf("line %d: expected key, found %v", line, token)
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		object = append(object, hclItem{key: key, value: value, line: line})
+	}
+
+	// the closing brace
+	if _, err := p.decoder.Token(); err != nil {
+		return nil, errors.Wrapf(err, "line %d: invalid JSON", p.line())
+	}
+	return object, nil
+}
+
+func (p *jsonParser) list() ([]interface{}, error) {
+	list := []interface{}{}
+	for p.decoder.More() {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+
+	// the closing bracket
+	if _, err := p.decoder.Token(); err != nil {
+		return nil, errors.Wrapf(err, "line %d: invalid JSON", p.line())
+	}
+	return list, nil
+}
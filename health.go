@@ -0,0 +1,189 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"gophers.dev/pkgs/ignore"
+)
+
+// A Health is returned upon requesting health status from vault
+// and contains some metadata about the vault configuartion.
+//
+// More information about the meaning of each status can be found here:
+// https://www.vaultproject.io/api/system/health.html
+type Health struct {
+	Initialized                bool   `json:"initialized"`
+	Sealed                     bool   `json:"sealed"`
+	Standby                    bool   `json:"standby"`
+	PerformanceStandby         bool   `json:"performance_standby"`
+	ReplicationPerformanceMode string `json:"replication_performance_mode"`
+	ReplicationDRMode          string `json:"replication_dr_mode"`
+	ServerTimeUTC              int    `json:"server_time_utc"`
+	Version                    string `json:"version"`
+	ClusterName                string `json:"cluster_name"`
+	ClusterID                  string `json:"cluster_id"`
+
+	// StatusCode is the HTTP status code vault responded with,
+	// which reflects the state of the server.
+	StatusCode int `json:"-"`
+}
+
+// HealthOptions are used to configure which HTTP status codes vault
+// responds with when reporting health. Any codes left unset use the
+// defaults documented by vault.
+type HealthOptions struct {
+	StandbyOK       bool
+	PerfStandbyOK   bool
+	ActiveCode      int
+	StandbyCode     int
+	DRSecondaryCode int
+	PerfStandbyCode int
+	SealedCode      int
+	UninitCode      int
+}
+
+// the status codes vault may respond with when reporting health
+var healthCodes = []int{
+	http.StatusOK,                 // initialized, unsealed, and active
+	429,                           // unsealed and standby
+	472,                           // disaster recovery secondary
+	473,                           // performance standby
+	http.StatusNotImplemented,     // not initialized
+	http.StatusServiceUnavailable, // sealed
+}
+
+func (opts HealthOptions) codes() map[int]bool {
+	codes := make(map[int]bool)
+	for _, code := range healthCodes {
+		codes[code] = true
+	}
+	for _, code := range []int{
+		opts.ActiveCode,
+		opts.StandbyCode,
+		opts.DRSecondaryCode,
+		opts.PerfStandbyCode,
+		opts.SealedCode,
+		opts.UninitCode,
+	} {
+		if code > 0 {
+			codes[code] = true
+		}
+	}
+	return codes
+}
+
+func codeParam(name string, code int) [2]string {
+	if code <= 0 {
+		return [2]string{name, ""}
+	}
+	return [2]string{name, strconv.Itoa(code)}
+}
+
+func boolParam(name string, value bool) [2]string {
+	if !value {
+		return [2]string{name, ""}
+	}
+	return [2]string{name, "true"}
+}
+
+func (opts HealthOptions) path() string {
+	return fixup("/v1", "sys/health",
+		boolParam("standbyok", opts.StandbyOK),
+		boolParam("perfstandbyok", opts.PerfStandbyOK),
+		codeParam("activecode", opts.ActiveCode),
+		codeParam("standbycode", opts.StandbyCode),
+		codeParam("drsecondarycode", opts.DRSecondaryCode),
+		codeParam("performancestandbycode", opts.PerfStandbyCode),
+		codeParam("sealedcode", opts.SealedCode),
+		codeParam("uninitcode", opts.UninitCode),
+	)
+}
+
+func (c *client) Health() (Health, error) {
+	for _, address := range c.opts.Servers {
+		health, err := c.ServerHealth(address, HealthOptions{})
+		if err != nil {
+			c.opts.Logger.Printf("GET request for health failed: %v", err)
+			continue
+		}
+		return health, nil
+	}
+	return Health{}, errors.Errorf("failed to read health from: %v", c.opts.Servers)
+}
+
+// ServerHealth returns the health of the vault server at address,
+// which need not be one of the configured servers.
+func (c *client) ServerHealth(address string, opts HealthOptions) (Health, error) {
+	url := address + opts.path()
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Health{}, errors.Wrapf(err, "failed to build GET request to %q", url)
+	}
+
+	// the health endpoint does not require a token
+	request.Header.Set(headerContentType, mimeText)
+	c.setHeaders(request)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return Health{}, errors.Wrapf(err, "failed to execute GET request to %q", url)
+	}
+
+	defer ignore.Drain(response.Body)
+
+	// vault reports the state of the server through the status code, and
+	// includes the health information no matter which code is used
+	if !opts.codes()[response.StatusCode] {
+		return Health{}, errors.Errorf("bad status code: %d, url: %s", response.StatusCode, url)
+	}
+
+	var health Health
+	if err := json.NewDecoder(response.Body).Decode(&health); err != nil {
+		return Health{}, errors.Wrapf(err, "failed to read response from %q", url)
+	}
+	health.StatusCode = response.StatusCode
+
+	return health, nil
+}
+
+// ServersHealth returns the health of each of the configured servers, keyed
+// by address. Servers which could not be reached are omitted from the result
+// and reported in the returned error.
+func (c *client) ServersHealth(opts HealthOptions) (map[string]Health, error) {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	healths := make(map[string]Health, len(c.opts.Servers))
+	var failed []string
+
+	for _, address := range c.opts.Servers {
+		wg.Add(1)
+		go func(address string) {
+			defer wg.Done()
+			health, err := c.ServerHealth(address, opts)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				c.opts.Logger.Printf("GET request for health failed: %v", err)
+				failed = append(failed, address)
+				return
+			}
+			healths[address] = health
+		}(address)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return healths, errors.Errorf("failed to read health from: %s", strings.Join(failed, ", "))
+	}
+	return healths, nil
+}
@@ -0,0 +1,315 @@
+package vaultapi
+
+import (
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A LeaseManager keeps leases of dynamic secrets alive by renewing them in
+// the background ahead of their expiration. Leases that can no longer be
+// extended are reported through LeaseManagerOptions.MaxTTLReached, so that
+// fresh credentials can be acquired before the old ones expire.
+type LeaseManager interface {
+	// Register begins tracking the lease with the given id, which
+	// was issued with a lease duration of duration. An error is
+	// returned if the LeaseManager has been closed.
+	Register(id string, duration time.Duration) error
+
+	// Unregister stops tracking the lease with the given id. The
+	// lease is not revoked.
+	Unregister(id string)
+
+	// Close stops renewing leases and revokes every lease that is
+	// still registered.
+	Close() error
+}
+
+// LeaseManagerOptions are used to configure a LeaseManager
+// upon creation.
+type LeaseManagerOptions struct {
+	// Increment is the lease duration requested upon each renewal. If
+	// not set, the duration with which the lease was registered is used.
+	Increment time.Duration
+
+	// MaxTTLReached is called with the id of a lease that can no longer
+	// be extended, either because it has hit its max TTL or because it is
+	// not renewable. The lease is not renewed again, but it remains
+	// registered and will be revoked upon Close. It is called on a
+	// goroutine of its own, so that it may take its time acquiring fresh
+	// credentials, or call Close, without holding up other renewals.
+	MaxTTLReached func(id string)
+
+	// Logger may be optionally configured as an output for trace
+	// level logging produced by the LeaseManager.
+	Logger *log.Logger
+}
+
+type managedLease struct {
+	duration time.Duration
+	expires  time.Time
+	renewAt  time.Time
+	done     bool
+}
+
+// a leaseClock provides the time to a LeaseManager, so
+// that tests need not wait for leases to be renewed
+type leaseClock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) (<-chan time.Time, func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+	return timer.C, timer.Stop
+}
+
+type leaseManager struct {
+	sys   Sys
+	opts  LeaseManagerOptions
+	clock leaseClock
+
+	lock   sync.Mutex
+	leases map[string]*managedLease
+	closed bool
+
+	wakeup  chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewLeaseManager creates a LeaseManager which uses sys to renew and revoke
+// the leases registered with it. The background goroutine runs until Close
+// is called.
+func NewLeaseManager(sys Sys, opts LeaseManagerOptions) LeaseManager {
+	return newLeaseManager(sys, opts, realClock{})
+}
+
+func newLeaseManager(sys Sys, opts LeaseManagerOptions, clock leaseClock) *leaseManager {
+	if opts.Logger == nil {
+		opts.Logger = log.New(ioutil.Discard, "", 0)
+	}
+
+	if opts.MaxTTLReached == nil {
+		opts.MaxTTLReached = func(string) {}
+	}
+
+	m := &leaseManager{
+		sys:     sys,
+		opts:    opts,
+		clock:   clock,
+		leases:  make(map[string]*managedLease),
+		wakeup:  make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go m.run()
+
+	return m
+}
+
+// renew when two thirds of the lease duration has elapsed, which leaves
+// some time to retry in case vault is temporarily unavailable
+func renewalTime(now time.Time, duration time.Duration) time.Time {
+	return now.Add(duration * 2 / 3)
+}
+
+func (m *leaseManager) Register(id string, duration time.Duration) error {
+	now := m.clock.Now()
+
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return errors.Errorf("lease manager is closed, cannot register lease %q", id)
+	}
+	m.leases[id] = &managedLease{
+		duration: duration,
+		expires:  now.Add(duration),
+		renewAt:  renewalTime(now, duration),
+	}
+	m.lock.Unlock()
+
+	m.opts.Logger.Printf("lease manager registered lease %q", id)
+	m.poke()
+	return nil
+}
+
+func (m *leaseManager) Unregister(id string) {
+	m.lock.Lock()
+	delete(m.leases, id)
+	m.lock.Unlock()
+
+	m.opts.Logger.Printf("lease manager unregistered lease %q", id)
+	m.poke()
+}
+
+func (m *leaseManager) Close() error {
+	m.once.Do(func() {
+		close(m.stop)
+	})
+	<-m.stopped
+
+	m.lock.Lock()
+	m.closed = true
+	ids := make([]string, 0, len(m.leases))
+	for id := range m.leases {
+		ids = append(ids, id)
+	}
+	m.leases = make(map[string]*managedLease)
+	m.lock.Unlock()
+
+	sort.Strings(ids)
+
+	var failed []string
+	for _, id := range ids {
+		if err := m.sys.RevokeLease(id); err != nil {
+			m.opts.Logger.Printf("lease manager failed to revoke lease %q: %v", id, err)
+			failed = append(failed, id)
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("failed to revoke leases: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// poke wakes up the run loop so that it can recompute
+// when the next renewal should happen
+func (m *leaseManager) poke() {
+	select {
+	case m.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (m *leaseManager) run() {
+	defer close(m.stopped)
+
+	for {
+		timer, stop := m.clock.NewTimer(m.untilNext(m.clock.Now()))
+		select {
+		case <-m.stop:
+			stop()
+			return
+		case <-m.wakeup:
+			stop()
+		case now := <-timer:
+			m.renewDue(now)
+		}
+	}
+}
+
+// untilNext returns how long to wait until the next lease should be renewed,
+// or a long time if there are no leases that need renewing
+func (m *leaseManager) untilNext(now time.Time) time.Duration {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	next := 24 * time.Hour
+	for _, lease := range m.leases {
+		if lease.done {
+			continue
+		}
+		if wait := lease.renewAt.Sub(now); wait < next {
+			next = wait
+		}
+	}
+
+	if next < 0 {
+		return 0
+	}
+	return next
+}
+
+func (m *leaseManager) due(now time.Time) []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var ids []string
+	for id, lease := range m.leases {
+		if !lease.done && !lease.renewAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (m *leaseManager) renewDue(now time.Time) {
+	for _, id := range m.due(now) {
+		m.renew(id)
+	}
+}
+
+func (m *leaseManager) renew(id string) {
+	m.lock.Lock()
+	lease, exists := m.leases[id]
+	if !exists {
+		// unregistered while renewals were in progress
+		m.lock.Unlock()
+		return
+	}
+	increment := m.opts.Increment
+	if increment <= 0 {
+		increment = lease.duration
+	}
+	m.lock.Unlock()
+
+	renewed, err := m.sys.RenewLease(id, increment)
+	now := m.clock.Now()
+
+	m.lock.Lock()
+	lease, exists = m.leases[id]
+	if !exists {
+		m.lock.Unlock()
+		return
+	}
+
+	if err != nil {
+		m.opts.Logger.Printf("lease manager failed to renew lease %q: %v", id, err)
+		remaining := lease.expires.Sub(now)
+		if remaining > time.Second {
+			// try again after a third of the remaining time
+			lease.renewAt = now.Add(remaining / 3)
+			m.lock.Unlock()
+			return
+		}
+		// out of time, the lease is as good as expired
+		lease.done = true
+		m.lock.Unlock()
+		go m.opts.MaxTTLReached(id)
+		return
+	}
+
+	duration := time.Duration(renewed.LeaseDuration) * time.Second
+	lease.expires = now.Add(duration)
+	lease.renewAt = renewalTime(now, duration)
+
+	// vault caps the lease duration of a renewal at the max TTL
+	// of the lease, which means the lease cannot be extended further,
+	// where the increment is sent to vault in whole seconds
+	if !renewed.Renewable || duration < increment.Truncate(time.Second) {
+		lease.done = true
+		m.lock.Unlock()
+		m.opts.Logger.Printf("lease manager reached max ttl of lease %q", id)
+		go m.opts.MaxTTLReached(id)
+		return
+	}
+
+	m.lock.Unlock()
+	m.opts.Logger.Printf("lease manager renewed lease %q for %v", id, duration)
+}
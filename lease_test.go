@@ -0,0 +1,281 @@
+package vaultapi
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLeases implements the lease endpoints of Sys, where each lease
+// may be renewed until it has been extended maxRenewals times
+type fakeLeases struct {
+	Sys
+
+	lock        sync.Mutex
+	maxRenewals int
+	renewals    map[string]int
+	revoked     []string
+}
+
+func newFakeLeases(maxRenewals int) *fakeLeases {
+	return &fakeLeases{
+		maxRenewals: maxRenewals,
+		renewals:    make(map[string]int),
+	}
+}
+
+func (f *fakeLeases) RenewLease(id string, increment time.Duration) (RenewedLease, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if id == "broken" {
+		return RenewedLease{}, errors.New("renew failed")
+	}
+
+	f.renewals[id]++
+	duration := increment
+	if f.renewals[id] >= f.maxRenewals {
+		duration = increment / 2
+	}
+
+	return RenewedLease{
+		ID:            id,
+		LeaseDuration: int(duration.Seconds()),
+		Renewable:     true,
+	}, nil
+}
+
+func (f *fakeLeases) RevokeLease(id string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if id == "broken" {
+		return errors.New("revoke failed")
+	}
+
+	f.revoked = append(f.revoked, id)
+	return nil
+}
+
+func (f *fakeLeases) count(id string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.renewals[id]
+}
+
+// fakeClock is a leaseClock which only moves when advanced, and
+// which reports each timer created by the LeaseManager on created
+type fakeClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	created chan struct{}
+}
+
+type fakeTimer struct {
+	when    time.Time
+	c       chan time.Time
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		created: make(chan struct{}, 100),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	timer := &fakeTimer{when: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	c.created <- struct{}{}
+
+	return timer.c, func() bool {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		timer.stopped = true
+		return true
+	}
+}
+
+// advance moves the clock forward by d, firing the timers that are due
+func (c *fakeClock) advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+	var pending []*fakeTimer
+	for _, timer := range c.timers {
+		switch {
+		case timer.stopped:
+		case !timer.when.After(c.now):
+			timer.c <- c.now
+		default:
+			pending = append(pending, timer)
+		}
+	}
+	c.timers = pending
+}
+
+// waitTimer waits for the LeaseManager to create its next timer,
+// which it does once it is done handling the previous one
+func (c *fakeClock) waitTimer(t *testing.T) {
+	select {
+	case <-c.created:
+	case <-time.After(10 * time.Second):
+		t.Fatal("lease manager did not create a timer")
+	}
+}
+
+func Test_LeaseManager_MaxTTL(t *testing.T) {
+	sys := newFakeLeases(2)
+	clock := newFakeClock()
+	reached := make(chan string, 1)
+	manager := newLeaseManager(sys, LeaseManagerOptions{
+		MaxTTLReached: func(id string) {
+			reached <- id
+		},
+	}, clock)
+	clock.waitTimer(t)
+
+	require.NoError(t, manager.Register("database/creds/r1/abc", 3*time.Second))
+	clock.waitTimer(t)
+
+	clock.advance(2 * time.Second)
+	clock.waitTimer(t)
+	require.Equal(t, 1, sys.count("database/creds/r1/abc"))
+	require.Empty(t, reached)
+
+	clock.advance(2 * time.Second)
+	clock.waitTimer(t)
+	require.Equal(t, 2, sys.count("database/creds/r1/abc"))
+	require.Equal(t, "database/creds/r1/abc", <-reached)
+
+	// the lease is not renewed again, even once the idle timer fires
+	clock.advance(25 * time.Hour)
+	clock.waitTimer(t)
+	require.Equal(t, 2, sys.count("database/creds/r1/abc"))
+
+	require.NoError(t, manager.Close())
+	require.Equal(t, []string{"database/creds/r1/abc"}, sys.revoked)
+}
+
+func Test_LeaseManager_MaxTTL_Close(t *testing.T) {
+	sys := newFakeLeases(1)
+	clock := newFakeClock()
+	closed := make(chan error, 1)
+	var manager LeaseManager
+	manager = newLeaseManager(sys, LeaseManagerOptions{
+		// the natural reaction to reaching the max ttl
+		MaxTTLReached: func(string) {
+			closed <- manager.Close()
+		},
+	}, clock)
+	clock.waitTimer(t)
+
+	require.NoError(t, manager.Register("l1", 3*time.Second))
+	clock.waitTimer(t)
+	clock.advance(2 * time.Second)
+
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("closing from MaxTTLReached deadlocked")
+	}
+	require.Equal(t, []string{"l1"}, sys.revoked)
+}
+
+func Test_LeaseManager_Increment(t *testing.T) {
+	sys := newFakeLeases(100)
+	clock := newFakeClock()
+	reached := make(chan string, 1)
+	manager := newLeaseManager(sys, LeaseManagerOptions{
+		// sent to vault as 3 seconds
+		Increment: 3500 * time.Millisecond,
+		MaxTTLReached: func(id string) {
+			reached <- id
+		},
+	}, clock)
+	clock.waitTimer(t)
+
+	require.NoError(t, manager.Register("l1", 3*time.Second))
+	clock.waitTimer(t)
+
+	clock.advance(2 * time.Second)
+	clock.waitTimer(t)
+	require.Equal(t, 1, sys.count("l1"))
+
+	// the lease is still being renewed
+	clock.advance(2 * time.Second)
+	clock.waitTimer(t)
+	require.Equal(t, 2, sys.count("l1"))
+	require.Empty(t, reached)
+
+	require.NoError(t, manager.Close())
+}
+
+func Test_LeaseManager_Unregister(t *testing.T) {
+	sys := newFakeLeases(100)
+	clock := newFakeClock()
+	manager := newLeaseManager(sys, LeaseManagerOptions{}, clock)
+	clock.waitTimer(t)
+
+	require.NoError(t, manager.Register("l1", 3*time.Second))
+	clock.waitTimer(t)
+	require.NoError(t, manager.Register("l2", 3*time.Second))
+	clock.waitTimer(t)
+	manager.Unregister("l1")
+	clock.waitTimer(t)
+
+	clock.advance(2500 * time.Millisecond)
+	clock.waitTimer(t)
+	require.Equal(t, 0, sys.count("l1"))
+	require.Equal(t, 1, sys.count("l2"))
+
+	require.NoError(t, manager.Close())
+	require.Equal(t, []string{"l2"}, sys.revoked)
+}
+
+func Test_LeaseManager_RegisterClosed(t *testing.T) {
+	sys := newFakeLeases(100)
+	manager := NewLeaseManager(sys, LeaseManagerOptions{})
+	require.NoError(t, manager.Close())
+
+	err := manager.Register("l1", time.Hour)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "closed")
+
+	// nothing was left behind to revoke
+	require.NoError(t, manager.Close())
+	require.Empty(t, sys.revoked)
+}
+
+func Test_LeaseManager_CloseError(t *testing.T) {
+	sys := newFakeLeases(100)
+	manager := NewLeaseManager(sys, LeaseManagerOptions{})
+
+	require.NoError(t, manager.Register("broken", 1*time.Hour))
+	require.NoError(t, manager.Register("l1", 1*time.Hour))
+
+	err := manager.Close()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "broken")
+	require.Equal(t, []string{"l1"}, sys.revoked)
+
+	// closing again has nothing left to revoke
+	require.NoError(t, manager.Close())
+}
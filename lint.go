@@ -0,0 +1,298 @@
+package vaultapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Severity indicates how concerning a PolicyFinding is.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// The checks which may be reported in a PolicyFinding.
+const (
+	CheckSudoGlob         = "sudo-glob"
+	CheckRootGlob         = "root-glob"
+	CheckSysWrite         = "sys-write"
+	CheckDeprecatedPolicy = "deprecated-policy"
+	CheckUnusedRule       = "unused-rule"
+	CheckUnusedParameters = "unused-parameters"
+	CheckShadowedRule     = "shadowed-rule"
+	CheckDuplicatePath    = "duplicate-path"
+	CheckMergedRule       = "merged-rule"
+)
+
+// A PolicyFinding describes a potential problem with one
+// path rule of a policy.
+type PolicyFinding struct {
+	Policy   string
+	Path     string
+	Severity Severity
+	Check    string
+	Message  string
+}
+
+func (f PolicyFinding) String() string {
+	return fmt.Sprintf("%s: policy %q, path %q: %s (%s)", f.Severity, f.Policy, f.Path, f.Message, f.Check)
+}
+
+// capabilities which modify what is stored at a path
+var writeCapabilities = []string{CapabilityCreate, CapabilityUpdate, CapabilityDelete, CapabilitySudo}
+
+// LintPolicy checks the rules of policies for dangerous or needlessly
+// broad grants, for deprecated syntax, and for rules which have no effect,
+// including rules which never take effect because another rule of higher
+// priority matches every path they match. The policies are treated as the
+// set of policies assigned to one token, so rules of one policy may shadow
+// rules of another, and rules with the same path in more than one of the
+// policies are reported, as vault merges them together. To lint unrelated
+// policies, call LintPolicy once for each policy.
+// The findings are ordered from most to least severe.
+func LintPolicy(policies ...Policy) []PolicyFinding {
+	var findings []PolicyFinding
+	owners := make(map[string][]string)
+
+	for _, policy := range policies {
+		seen := make(map[string]bool)
+		for _, rule := range policy.Paths {
+			report := func(severity Severity, check, message string) {
+				findings = append(findings, PolicyFinding{
+					Policy:   policy.Name,
+					Path:     rule.Path,
+					Severity: severity,
+					Check:    check,
+					Message:  message,
+				})
+			}
+
+			lintRule(rule, report)
+
+			if seen[rule.Path] {
+				report(SeverityWarning, CheckDuplicatePath, "path is defined more than once in the policy, and the rules are merged")
+			} else if !containsString(owners[rule.Path], policy.Name) {
+				owners[rule.Path] = append(owners[rule.Path], policy.Name)
+			}
+			seen[rule.Path] = true
+		}
+	}
+
+	for path, names := range owners {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			findings = append(findings, PolicyFinding{
+				Policy:   name,
+				Path:     path,
+				Severity: SeverityInfo,
+				Check:    CheckMergedRule,
+				Message:  fmt.Sprintf("path is also defined in policies %v, and the rules are merged", others(names, name)),
+			})
+		}
+	}
+
+	for path, names := range owners {
+		shadow, exists := shadowedBy(path, owners)
+		if !exists {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			findings = append(findings, PolicyFinding{
+				Policy:   name,
+				Path:     path,
+				Severity: SeverityWarning,
+				Check:    CheckShadowedRule,
+				Message:  fmt.Sprintf("rule never takes effect, as %q of policies %v has higher priority on every path it matches", shadow, owners[shadow]),
+			})
+		}
+	}
+
+	sortFindings(findings)
+	return findings
+}
+
+// sortFindings orders findings from most to least severe
+func sortFindings(findings []PolicyFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		return a.Path < b.Path
+	})
+}
+
+// shadowedBy returns the rule of highest priority among paths which matches
+// every path matched by the rule path, if that rule has higher priority
+func shadowedBy(path string, paths map[string][]string) (string, bool) {
+	var best string
+	for other := range paths {
+		if other == path || !coversPolicyPath(other, path) {
+			continue
+		}
+		if best == "" || lowerPriority(trimSlash(best), trimSlash(other)) {
+			best = other
+		}
+	}
+
+	if best == "" || !lowerPriority(trimSlash(path), trimSlash(best)) {
+		return "", false
+	}
+	return best, true
+}
+
+func trimSlash(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+// coversPolicyPath returns whether every path matched by the rule path
+// inner is also matched by the rule path outer, following the same rules
+// as matchPolicyPath
+func coversPolicyPath(outer, inner string) bool {
+	outer, inner = trimSlash(outer), trimSlash(inner)
+
+	outerGlob, innerGlob := strings.HasSuffix(outer, "*"), strings.HasSuffix(inner, "*")
+	outerSegments := strings.Split(strings.TrimSuffix(outer, "*"), "/")
+	innerSegments := strings.Split(strings.TrimSuffix(inner, "*"), "/")
+
+	// paths matched by inner have at least as many segments as inner, and
+	// exactly as many unless inner ends in a glob
+	switch {
+	case outerGlob && len(innerSegments) < len(outerSegments):
+		return false
+	case !outerGlob && (innerGlob || len(innerSegments) != len(outerSegments)):
+		return false
+	}
+
+	for i, segment := range outerSegments {
+		last := i == len(outerSegments)-1
+		innerSegment := innerSegments[i]
+		innerPrefix := innerGlob && i == len(innerSegments)-1
+		switch {
+		case segment == "+":
+			// matches any one segment
+		case last && outerGlob:
+			if segment != "" && (innerSegment == "+" || !strings.HasPrefix(innerSegment, segment)) {
+				return false
+			}
+		case innerSegment == "+" || innerPrefix || innerSegment != segment:
+			return false
+		}
+	}
+
+	return true
+}
+
+func others(names []string, name string) []string {
+	var result []string
+	for _, each := range names {
+		if each != name {
+			result = append(result, each)
+		}
+	}
+	return result
+}
+
+func lintRule(rule PathRule, report func(Severity, string, string)) {
+	capabilities := rule.EffectiveCapabilities()
+	has := func(capability string) bool {
+		return containsString(capabilities, capability)
+	}
+	denied := has(CapabilityDeny)
+	glob := strings.ContainsAny(rule.Path, "+*")
+
+	if rule.Policy != "" {
+		report(SeverityInfo, CheckDeprecatedPolicy, fmt.Sprintf("policy = %q is deprecated, use capabilities = %q instead", rule.Policy, capabilities))
+	}
+
+	if len(capabilities) == 0 {
+		report(SeverityWarning, CheckUnusedRule, "rule grants no capabilities")
+		return
+	}
+
+	if denied {
+		if len(capabilities) > 1 {
+			report(SeverityWarning, CheckUnusedRule, "deny overrides every other capability granted by the rule")
+		}
+		return
+	}
+
+	if glob && has(CapabilitySudo) {
+		report(SeverityCritical, CheckSudoGlob, "sudo is granted on every path matching a wildcard")
+	}
+
+	switch {
+	case trimSlash(rule.Path) == "*":
+		report(SeverityCritical, CheckRootGlob, "rule matches every path in vault")
+	case firstWildcard(trimSlash(rule.Path)) == 0:
+		report(SeverityCritical, CheckRootGlob, "rule matches paths of every mount in vault, as it begins with a wildcard")
+	}
+
+	if strings.HasPrefix(strings.TrimPrefix(rule.Path, "/"), "sys/") {
+		for _, capability := range writeCapabilities {
+			if has(capability) {
+				report(SeverityWarning, CheckSysWrite, fmt.Sprintf("%s is granted on the system backend", capability))
+				break
+			}
+		}
+	}
+
+	// parameter constraints only apply to create and update requests
+	constrained := len(rule.AllowedParameters) > 0 || len(rule.DeniedParameters) > 0 || len(rule.RequiredParameters) > 0
+	if constrained && !has(CapabilityCreate) && !has(CapabilityUpdate) {
+		report(SeverityInfo, CheckUnusedParameters, "parameter constraints have no effect without create or update")
+	}
+}
+
+// LintPolicies lints each policy in vault on its own, as policies in vault
+// are not necessarily assigned to the same tokens. Rules shadowed by or
+// merged with rules of other policies of a token can be found by passing
+// the policies of the token to LintPolicy.
+func (c *client) LintPolicies() ([]PolicyFinding, error) {
+	names, err := c.ListPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []PolicyFinding
+	for _, name := range names {
+		// the root policy cannot be read, and contains no rules
+		if name == "root" {
+			continue
+		}
+
+		policy, err := c.GetPolicyParsed(name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to lint policies")
+		}
+		findings = append(findings, LintPolicy(policy)...)
+	}
+
+	sortFindings(findings)
+	return findings, nil
+}
@@ -0,0 +1,205 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// the policy written by hack/travis-run.sh
+const pol3 = `
+path "sys/*" {
+    policy = "deny"
+}
+
+path "secret/my/stuff/*" {
+    capabilities = ["read", "create", "update", "list"]
+}
+
+path "auth/token/lookup-self" {
+    policy = "read"
+}
+
+path "auth/token/renew-self" {
+    policy = "write"
+}
+`
+
+func checks(findings []PolicyFinding) []string {
+	var result []string
+	for _, finding := range findings {
+		result = append(result, finding.Severity.String()+" "+finding.Check+" "+finding.Path)
+	}
+	return result
+}
+
+func Test_LintPolicy_deprecated(t *testing.T) {
+	findings := LintPolicy(parsePolicy(t, "my_policy1", pol3))
+	require.Equal(t, []string{
+		"info deprecated-policy auth/token/lookup-self",
+		"info deprecated-policy auth/token/renew-self",
+		"info deprecated-policy sys/*",
+	}, checks(findings))
+	require.Equal(t, "my_policy1", findings[0].Policy)
+}
+
+func Test_LintPolicy_dangerous(t *testing.T) {
+	findings := LintPolicy(parsePolicy(t, "admin", `
+path "*" {
+  capabilities = ["read", "list"]
+}
+
+path "sys/leases/*" {
+  capabilities = ["update", "sudo"]
+}
+
+path "sys/mounts" {
+  capabilities = ["read"]
+}
+
+path "secret/foo" {
+  capabilities = ["deny", "read"]
+}
+
+path "secret/bar" {
+  capabilities = []
+}
+
+path "secret/baz" {
+  capabilities = ["read"]
+  allowed_parameters = {
+    "region" = []
+  }
+}
+
+path "secret/baz" {
+  capabilities = ["list"]
+}`))
+
+	require.Equal(t, []string{
+		"critical root-glob *",
+		"critical sudo-glob sys/leases/*",
+		"warning unused-rule secret/bar",
+		"warning duplicate-path secret/baz",
+		"warning unused-rule secret/foo",
+		"warning sys-write sys/leases/*",
+		"info unused-parameters secret/baz",
+	}, checks(findings))
+}
+
+func Test_LintPolicy_shadowed(t *testing.T) {
+	a := parsePolicy(t, "a", `
+path "secret/*" { capabilities = ["read"] }
+path "secret/+/foo" { capabilities = ["list"] }
+path "+/admin" { capabilities = ["read"] }
+`)
+	b := parsePolicy(t, "b", `
+path "secret/+/*" { capabilities = ["update"] }
+path "secret/+/bar*" { capabilities = ["update"] }
+path "secret/foo/*" { capabilities = ["update"] }
+`)
+
+	findings := LintPolicy(a, b)
+	require.Equal(t, []string{
+		"critical root-glob +/admin",
+		"warning shadowed-rule secret/+/*",
+		"warning shadowed-rule secret/+/bar*",
+	}, checks(findings))
+	require.Equal(t, "b", findings[1].Policy)
+	require.Contains(t, findings[1].Message, `"secret/*"`)
+	require.Contains(t, findings[0].Message, "every mount")
+
+	// the evaluator never chooses a shadowed rule
+	evaluator, err := NewPolicyEvaluator(a, b)
+	require.NoError(t, err)
+	for _, path := range []string{"secret/x/y", "secret/x/", "secret/x/bar", "secret/x/barbaz/y"} {
+		rule := evaluator.Explain(path).Rule
+		require.Equal(t, "secret/*", rule, "path: %s", path)
+	}
+	require.Equal(t, "secret/+/foo", evaluator.Explain("secret/x/foo").Rule)
+	require.Equal(t, "secret/foo/*", evaluator.Explain("secret/foo/x").Rule)
+}
+
+func Test_coversPolicyPath(t *testing.T) {
+	for _, tc := range []struct {
+		outer, inner string
+		covers       bool
+	}{
+		{"secret/*", "secret/+/*", true},
+		{"secret/*", "secret/foo", true},
+		{"secret/*", "secret*", false},
+		{"secret/f*", "secret/foo/+", true},
+		{"secret/f*", "secret/+/foo", false},
+		{"secret/+", "secret/foo", true},
+		{"secret/+", "secret/foo/bar", false},
+		{"secret/+", "secret/*", false},
+		{"secret/+/*", "secret/foo/bar*", true},
+		{"secret/+/b*", "secret/foo/+", false},
+		{"secret/foo", "secret/foo", true},
+		{"secret/foo", "secret/+", false},
+		{"*", "+/foo", true},
+	} {
+		require.Equal(t, tc.covers, coversPolicyPath(tc.outer, tc.inner), "outer: %s, inner: %s", tc.outer, tc.inner)
+	}
+}
+
+func Test_LintPolicy_merged(t *testing.T) {
+	findings := LintPolicy(
+		parsePolicy(t, "a", `path "secret/*" { capabilities = ["read"] }`),
+		parsePolicy(t, "b", `path "secret/*" { capabilities = ["list"] }`),
+	)
+	require.Equal(t, 2, len(findings))
+	require.Equal(t, CheckMergedRule, findings[0].Check)
+	require.Equal(t, "a", findings[0].Policy)
+	require.Contains(t, findings[0].Message, "[b]")
+	require.Equal(t, "b", findings[1].Policy)
+}
+
+func Test_Client_LintPolicies_separately(t *testing.T) {
+	policies := map[string]string{
+		"a": `path "secret/*" { capabilities = ["read"] }`,
+		"b": `path "secret/*" { capabilities = ["list"] }
+path "secret/+/*" { capabilities = ["update"] }
+path "*" { capabilities = ["read"] }`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/sys/policy" {
+			_ = json.NewEncoder(w).Encode(map[string][]string{"policies": {"root", "a", "b"}})
+			return
+		}
+		rules, exists := policies[strings.TrimPrefix(r.URL.Path, "/v1/sys/policy/")]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"rules": rules})
+	}))
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	// policy a is neither merged with nor shadowed by policy b,
+	// as they need not be assigned to the same token
+	findings, err := client.LintPolicies()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"critical root-glob *",
+		"warning shadowed-rule secret/+/*",
+	}, checks(findings))
+	require.Equal(t, "b", findings[1].Policy)
+}
+
+func Test_Client_LintPolicies(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	findings, err := client.LintPolicies()
+	require.NoError(t, err)
+	for _, finding := range findings {
+		t.Log("finding:", finding)
+	}
+}
@@ -0,0 +1,119 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//go:generate go run github.com/shoenig/mockery3/v3/cmd/mockery3 -interface Logical -package vaultapitest
+
+// A Logical provides raw access to any path in vault. Paths are relative
+// to the /v1/ prefix of the vault HTTP API, for example "secret/foo" or
+// "database/creds/readonly". This can be used to reach backends that are
+// not otherwise supported by the Client.
+//
+// More information about the vault HTTP API can be found here:
+// https://www.vaultproject.io/api/index.html
+type Logical interface {
+	// Read returns the Secret stored at path.
+	Read(path string) (*Secret, error)
+	// Write sets data at path, returning the Secret produced by vault
+	// in response, if any.
+	Write(path string, data map[string]interface{}) (*Secret, error)
+	// List returns the keys under path in asciibetical order.
+	List(path string) ([]string, error)
+	// Delete removes whatever exists at path.
+	Delete(path string) error
+}
+
+// A Secret is the generic envelope of a response from vault.
+type Secret struct {
+	RequestID     string                 `json:"request_id"`
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+	Warnings      []string               `json:"warnings"`
+	Auth          *SecretAuth            `json:"auth"`
+	WrapInfo      *WrapInfo              `json:"wrap_info"`
+}
+
+// A SecretAuth is included in a Secret when the response from vault
+// contains a newly created token, for example after logging in.
+type SecretAuth struct {
+	ClientToken   string            `json:"client_token"`
+	Accessor      string            `json:"accessor"`
+	Policies      []string          `json:"policies"`
+	TokenPolicies []string          `json:"token_policies"`
+	Metadata      map[string]string `json:"metadata"`
+	LeaseDuration int               `json:"lease_duration"`
+	Renewable     bool              `json:"renewable"`
+	EntityID      string            `json:"entity_id"`
+	TokenType     string            `json:"token_type"`
+	Orphan        bool              `json:"orphan"`
+}
+
+// A WrapInfo is included in a Secret when the response from vault has
+// been wrapped in a single-use token.
+//
+// More information about response wrapping can be found here:
+// https://www.vaultproject.io/docs/concepts/response-wrapping.html
+type WrapInfo struct {
+	Token           string    `json:"token"`
+	Accessor        string    `json:"accessor"`
+	TTL             int       `json:"ttl"`
+	CreationTime    time.Time `json:"creation_time"`
+	CreationPath    string    `json:"creation_path"`
+	WrappedAccessor string    `json:"wrapped_accessor"`
+}
+
+type logical struct {
+	client *client
+}
+
+func (c *client) Logical(opts ...RequestOption) Logical {
+	return &logical{client: c.with(opts...)}
+}
+
+func (l *logical) Read(path string) (*Secret, error) {
+	var secret Secret
+	if err := l.client.get(fixup("/v1", path), &secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", path)
+	}
+	return &secret, nil
+}
+
+func (l *logical) Write(path string, data map[string]interface{}) (*Secret, error) {
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create json for writing %q", path)
+	}
+
+	// a nil secret implies vault responded without content
+	var secret *Secret
+	if err := l.client.post(fixup("/v1", path), string(bs), &secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to write %q", path)
+	}
+
+	return secret, nil
+}
+
+func (l *logical) List(path string) ([]string, error) {
+	var data keysData
+	if err := l.client.list(fixup("/v1", path), &data); err != nil {
+		return nil, errors.Wrapf(err, "failed to list %q", path)
+	}
+	keys := data.Data["keys"]
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (l *logical) Delete(path string) error {
+	if err := l.client.deleteKey(fixup("/v1", path)); err != nil {
+		return errors.Wrapf(err, "failed to delete %q", path)
+	}
+	return nil
+}
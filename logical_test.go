@@ -0,0 +1,61 @@
+package vaultapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Logical(t *testing.T) {
+	client := getClient(t, rootTokener)
+	logical := client.Logical()
+
+	secret, err := logical.Write("secret/logical/foo", map[string]interface{}{
+		"username": "bob",
+		"port":     5432,
+	})
+	require.NoError(t, err)
+	require.Nil(t, secret) // no content when writing kv
+
+	secret, err = logical.Read("secret/logical/foo")
+	require.NoError(t, err)
+	require.Equal(t, "bob", secret.Data["username"])
+	require.Equal(t, float64(5432), secret.Data["port"])
+	require.True(t, secret.LeaseDuration > 0)
+
+	keys, err := logical.List("secret/logical")
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, keys)
+
+	require.NoError(t, logical.Delete("secret/logical/foo"))
+
+	_, err = logical.Read("secret/logical/foo")
+	require.Equal(t, ErrPathNotFound, errors.Cause(err))
+
+	// endpoints which create tokens include auth in the response
+	secret, err = logical.Write("auth/token/create", map[string]interface{}{
+		"policies":  []string{"default"},
+		"no_parent": true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, secret.Auth)
+	require.NotEmpty(t, secret.Auth.ClientToken)
+	require.Equal(t, []string{"default"}, secret.Auth.Policies)
+}
+
+func Test_Logical_Delete_notFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	err = client.Logical().Delete("database/roles/missing")
+	require.Equal(t, ErrPathNotFound, errors.Cause(err))
+}
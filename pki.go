@@ -0,0 +1,482 @@
+package vaultapi
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//go:generate go run github.com/shoenig/mockery3/v3/cmd/mockery3 -interface PKI -package vaultapitest
+
+// A PKI issues and manages X.509 certificates through the
+// PKI secrets engine.
+//
+// More information about the PKI secrets engine can be found here:
+// https://www.vaultproject.io/api/secret/pki/index.html
+type PKI interface {
+	// Certificates
+	IssueCertificate(role string, opts CertificateOptions) (IssuedCertificate, error)
+	SignCSR(role string, csr []byte, opts CertificateOptions) (SignedCertificate, error)
+	SignIntermediate(csr []byte, opts CertificateOptions) (SignedCertificate, error)
+	RevokeCertificate(serial string) (time.Time, error)
+	ReadCertificate(serial string) (*x509.Certificate, error)
+	ListCertificates() ([]string, error)
+	ReadCA() (*x509.Certificate, error)
+	ReadCRL() (*pkix.CertificateList, error)
+	Tidy(opts TidyOptions) error
+
+	// Roles
+	CreateRole(name string, role PKIRole) error
+	ReadRole(name string) (PKIRole, error)
+	ListRoles() ([]string, error)
+	DeleteRole(name string) error
+}
+
+// CertificateOptions are used to configure the certificate
+// being issued or signed.
+type CertificateOptions struct {
+	CommonName        string
+	AltNames          []string
+	IPSANs            []string
+	URISANs           []string
+	TTL               time.Duration
+	ExcludeCNFromSANs bool
+}
+
+type certificateOptionsJSON struct {
+	CommonName        string `json:"common_name"`
+	AltNames          string `json:"alt_names,omitempty"`
+	IPSANs            string `json:"ip_sans,omitempty"`
+	URISANs           string `json:"uri_sans,omitempty"`
+	TTL               string `json:"ttl,omitempty"`
+	ExcludeCNFromSANs bool   `json:"exclude_cn_from_sans,omitempty"`
+	CSR               string `json:"csr,omitempty"`
+}
+
+func (opts CertificateOptions) request(csr []byte) (string, error) {
+	bs, err := json.Marshal(certificateOptionsJSON{
+		CommonName:        opts.CommonName,
+		AltNames:          strings.Join(opts.AltNames, ","),
+		IPSANs:            strings.Join(opts.IPSANs, ","),
+		URISANs:           strings.Join(opts.URISANs, ","),
+		TTL:               ttlString(opts.TTL),
+		ExcludeCNFromSANs: opts.ExcludeCNFromSANs,
+		CSR:               string(csr),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create json for certificate request")
+	}
+	return string(bs), nil
+}
+
+// A SignedCertificate is a certificate signed by the PKI secrets engine,
+// along with the chain of certificates of its issuing CA.
+type SignedCertificate struct {
+	Certificate  *x509.Certificate
+	Chain        []*x509.Certificate
+	SerialNumber string
+}
+
+// An IssuedCertificate is a SignedCertificate for which the PKI secrets
+// engine also generated the private key.
+type IssuedCertificate struct {
+	SignedCertificate
+	PrivateKey     crypto.Signer
+	PrivateKeyType string
+}
+
+// TLSCertificate returns ic in the form used by crypto/tls.
+func (ic IssuedCertificate) TLSCertificate() tls.Certificate {
+	certificate := tls.Certificate{
+		Certificate: [][]byte{ic.Certificate.Raw},
+		PrivateKey:  ic.PrivateKey,
+		Leaf:        ic.Certificate,
+	}
+	for _, ca := range ic.Chain {
+		certificate.Certificate = append(certificate.Certificate, ca.Raw)
+	}
+	return certificate
+}
+
+type signedCertificateWrapper struct {
+	Data struct {
+		Certificate    string   `json:"certificate"`
+		IssuingCA      string   `json:"issuing_ca"`
+		CAChain        []string `json:"ca_chain"`
+		PrivateKey     string   `json:"private_key"`
+		PrivateKeyType string   `json:"private_key_type"`
+		SerialNumber   string   `json:"serial_number"`
+	} `json:"data"`
+}
+
+func (w signedCertificateWrapper) signed() (SignedCertificate, error) {
+	certificate, err := parseCertificate(w.Data.Certificate)
+	if err != nil {
+		return SignedCertificate{}, errors.Wrap(err, "failed to parse certificate")
+	}
+
+	chainPEM := w.Data.CAChain
+	if len(chainPEM) == 0 && w.Data.IssuingCA != "" {
+		chainPEM = []string{w.Data.IssuingCA}
+	}
+
+	chain := make([]*x509.Certificate, 0, len(chainPEM))
+	for _, encoded := range chainPEM {
+		ca, err := parseCertificate(encoded)
+		if err != nil {
+			return SignedCertificate{}, errors.Wrap(err, "failed to parse ca chain")
+		}
+		chain = append(chain, ca)
+	}
+
+	return SignedCertificate{
+		Certificate:  certificate,
+		Chain:        chain,
+		SerialNumber: w.Data.SerialNumber,
+	}, nil
+}
+
+func parseCertificate(encoded string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("certificate is not PEM encoded")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parsePrivateKey(encoded string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.Errorf("unsupported private key %T", key)
+		}
+		return signer, nil
+	}
+
+	return nil, errors.Errorf("unsupported private key type %q", block.Type)
+}
+
+type pki struct {
+	client *client
+	mount  string
+}
+
+// PKI returns a PKI for the PKI secrets engine enabled at mount,
+// which is "pki" if mount is empty.
+func (c *client) PKI(mount string) PKI {
+	mount = strings.Trim(mount, "/")
+	if mount == "" {
+		mount = "pki"
+	}
+	return &pki{client: c, mount: mount}
+}
+
+func (p *pki) path(elements ...string) string {
+	return "/v1/" + p.mount + "/" + strings.Join(elements, "/")
+}
+
+func (p *pki) IssueCertificate(role string, opts CertificateOptions) (IssuedCertificate, error) {
+	body, err := opts.request(nil)
+	if err != nil {
+		return IssuedCertificate{}, err
+	}
+
+	var wrapper signedCertificateWrapper
+	if err := p.client.post(p.path("issue", role), body, &wrapper); err != nil {
+		return IssuedCertificate{}, errors.Wrapf(err, "failed to issue certificate for %q with role %q", opts.CommonName, role)
+	}
+
+	signed, err := wrapper.signed()
+	if err != nil {
+		return IssuedCertificate{}, err
+	}
+
+	key, err := parsePrivateKey(wrapper.Data.PrivateKey)
+	if err != nil {
+		// do not provide private key anywhere
+		return IssuedCertificate{}, errors.Wrap(err, "failed to parse private key")
+	}
+
+	return IssuedCertificate{
+		SignedCertificate: signed,
+		PrivateKey:        key,
+		PrivateKeyType:    wrapper.Data.PrivateKeyType,
+	}, nil
+}
+
+// SignCSR signs the PEM encoded certificate signing request csr, using the
+// constraints of role.
+func (p *pki) SignCSR(role string, csr []byte, opts CertificateOptions) (SignedCertificate, error) {
+	return p.sign(p.path("sign", role), csr, opts)
+}
+
+// SignIntermediate signs the PEM encoded certificate signing request csr as
+// an intermediate CA, using the CA of the PKI secrets engine.
+func (p *pki) SignIntermediate(csr []byte, opts CertificateOptions) (SignedCertificate, error) {
+	return p.sign(p.path("root", "sign-intermediate"), csr, opts)
+}
+
+func (p *pki) sign(path string, csr []byte, opts CertificateOptions) (SignedCertificate, error) {
+	body, err := opts.request(csr)
+	if err != nil {
+		return SignedCertificate{}, err
+	}
+
+	var wrapper signedCertificateWrapper
+	if err := p.client.post(path, body, &wrapper); err != nil {
+		return SignedCertificate{}, errors.Wrapf(err, "failed to sign certificate for %q", opts.CommonName)
+	}
+
+	return wrapper.signed()
+}
+
+func (p *pki) RevokeCertificate(serial string) (time.Time, error) {
+	bs, err := json.Marshal(struct {
+		SerialNumber string `json:"serial_number"`
+	}{SerialNumber: serial})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var wrapper struct {
+		Data struct {
+			RevocationTime int64 `json:"revocation_time"`
+		} `json:"data"`
+	}
+	if err := p.client.post(p.path("revoke"), string(bs), &wrapper); err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to revoke certificate %q", serial)
+	}
+
+	return time.Unix(wrapper.Data.RevocationTime, 0), nil
+}
+
+type certificateWrapper struct {
+	Data struct {
+		Certificate string `json:"certificate"`
+	} `json:"data"`
+}
+
+func (p *pki) readCertificate(serial string) (string, error) {
+	var wrapper certificateWrapper
+	if err := p.client.get(p.path("cert", serial), &wrapper); err != nil {
+		return "", err
+	}
+	return wrapper.Data.Certificate, nil
+}
+
+// ReadCertificate returns the certificate with the given serial number,
+// which is formatted as hyphen or colon separated hex octets.
+func (p *pki) ReadCertificate(serial string) (*x509.Certificate, error) {
+	encoded, err := p.readCertificate(serial)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read certificate %q", serial)
+	}
+
+	certificate, err := parseCertificate(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse certificate %q", serial)
+	}
+
+	return certificate, nil
+}
+
+func (p *pki) ListCertificates() ([]string, error) {
+	var data keysData
+	if err := p.client.list(p.path("certs"), &data); err != nil {
+		return nil, errors.Wrap(err, "failed to list certificates")
+	}
+	keys := data.Data["keys"]
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (p *pki) ReadCA() (*x509.Certificate, error) {
+	return p.ReadCertificate("ca")
+}
+
+func (p *pki) ReadCRL() (*pkix.CertificateList, error) {
+	encoded, err := p.readCertificate("crl")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read crl")
+	}
+
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil || block.Type != "X509 CRL" {
+		return nil, errors.New("crl is not PEM encoded")
+	}
+
+	crl, err := x509.ParseCRL(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse crl")
+	}
+
+	return crl, nil
+}
+
+// TidyOptions are used to configure which certificates
+// are removed from storage by Tidy.
+type TidyOptions struct {
+	// TidyCertStore removes expired certificates from storage.
+	TidyCertStore bool
+
+	// TidyRevokedCerts removes expired certificates from
+	// storage and from the CRL.
+	TidyRevokedCerts bool
+
+	// SafetyBuffer is how long after its expiration a certificate
+	// is kept, which defaults to 72 hours in vault.
+	SafetyBuffer time.Duration
+}
+
+// Tidy starts removing expired certificates from storage, which
+// vault continues to do in the background.
+func (p *pki) Tidy(opts TidyOptions) error {
+	bs, err := json.Marshal(struct {
+		TidyCertStore    bool   `json:"tidy_cert_store"`
+		TidyRevokedCerts bool   `json:"tidy_revoked_certs"`
+		SafetyBuffer     string `json:"safety_buffer,omitempty"`
+	}{
+		TidyCertStore:    opts.TidyCertStore,
+		TidyRevokedCerts: opts.TidyRevokedCerts,
+		SafetyBuffer:     ttlString(opts.SafetyBuffer),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := p.client.post(p.path("tidy"), string(bs), nil); err != nil {
+		return errors.Wrap(err, "failed to tidy certificates")
+	}
+
+	return nil
+}
+
+// A PKIRole constrains the certificates which may be issued
+// or signed using the role. The fields which vault defaults to true
+// are pointers, and along with the lists and the flags which vault
+// defaults to false are only sent when set, so that a role created with
+// only some fields set otherwise gets the defaults of vault, e.g. issuing
+// both server and client certificates.
+type PKIRole struct {
+	TTL              time.Duration `json:"-"`
+	MaxTTL           time.Duration `json:"-"`
+	AllowLocalhost   *bool         `json:"allow_localhost,omitempty"`
+	AllowedDomains   []string      `json:"allowed_domains,omitempty"`
+	AllowBareDomains bool          `json:"allow_bare_domains,omitempty"`
+	AllowSubdomains  bool          `json:"allow_subdomains,omitempty"`
+	AllowGlobDomains bool          `json:"allow_glob_domains,omitempty"`
+	AllowAnyName     bool          `json:"allow_any_name,omitempty"`
+	EnforceHostnames *bool         `json:"enforce_hostnames,omitempty"`
+	AllowIPSANs      *bool         `json:"allow_ip_sans,omitempty"`
+	AllowedURISANs   []string      `json:"allowed_uri_sans,omitempty"`
+	ServerFlag       *bool         `json:"server_flag,omitempty"`
+	ClientFlag       *bool         `json:"client_flag,omitempty"`
+	KeyType          string        `json:"key_type,omitempty"`
+	KeyBits          int           `json:"key_bits,omitempty"`
+	KeyUsage         []string      `json:"key_usage,omitempty"`
+	ExtKeyUsage      []string      `json:"ext_key_usage,omitempty"`
+	Organization     []string      `json:"organization,omitempty"`
+	OU               []string      `json:"ou,omitempty"`
+	GenerateLease    bool          `json:"generate_lease,omitempty"`
+	NoStore          bool          `json:"no_store,omitempty"`
+	RequireCN        *bool         `json:"require_cn,omitempty"`
+}
+
+// the fields of PKIRole without the custom json methods
+type pkiRoleFields PKIRole
+
+// MarshalJSON encodes role in the form expected by vault.
+func (role PKIRole) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		pkiRoleFields
+		TTL    string `json:"ttl,omitempty"`
+		MaxTTL string `json:"max_ttl,omitempty"`
+	}{
+		pkiRoleFields: pkiRoleFields(role),
+		TTL:           ttlString(role.TTL),
+		MaxTTL:        ttlString(role.MaxTTL),
+	})
+}
+
+// UnmarshalJSON decodes role from the form returned by vault.
+func (role *PKIRole) UnmarshalJSON(bs []byte) error {
+	response := struct {
+		*pkiRoleFields
+		TTL    int `json:"ttl"`
+		MaxTTL int `json:"max_ttl"`
+	}{pkiRoleFields: (*pkiRoleFields)(role)}
+
+	if err := json.Unmarshal(bs, &response); err != nil {
+		return err
+	}
+
+	role.TTL = time.Duration(response.TTL) * time.Second
+	role.MaxTTL = time.Duration(response.MaxTTL) * time.Second
+	return nil
+}
+
+// CreateRole creates the role called name, replacing the
+// role if it already exists.
+func (p *pki) CreateRole(name string, role PKIRole) error {
+	bs, err := json.Marshal(role)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for role %q", name)
+	}
+
+	if err := p.client.post(p.path("roles", name), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to create pki role %q", name)
+	}
+
+	return nil
+}
+
+type pkiRoleWrapper struct {
+	Data PKIRole `json:"data"`
+}
+
+func (p *pki) ReadRole(name string) (PKIRole, error) {
+	var wrapper pkiRoleWrapper
+	if err := p.client.get(p.path("roles", name), &wrapper); err != nil {
+		return PKIRole{}, errors.Wrapf(err, "failed to read pki role %q", name)
+	}
+	return wrapper.Data, nil
+}
+
+func (p *pki) ListRoles() ([]string, error) {
+	var data keysData
+	if err := p.client.list(p.path("roles"), &data); err != nil {
+		return nil, errors.Wrap(err, "failed to list pki roles")
+	}
+	keys := data.Data["keys"]
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (p *pki) DeleteRole(name string) error {
+	if err := p.client.deleteKey(p.path("roles", name)); err != nil {
+		return errors.Wrapf(err, "failed to delete pki role %q", name)
+	}
+	return nil
+}
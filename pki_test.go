@@ -0,0 +1,247 @@
+package vaultapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// getPKI enables the PKI secrets engine in the dev vault with a root CA
+// and a role for issuing certificates, if it is not already enabled
+func getPKI(t *testing.T) PKI {
+	client := getClient(t, rootTokener)
+
+	mounts, err := client.ListMounts()
+	require.NoError(t, err)
+	if _, exists := mounts["pki/"]; !exists {
+		day := 24 * time.Hour
+		err := client.EnableMount("pki", "pki", MountConfig{MaxLeaseTTL: &day})
+		require.NoError(t, err)
+
+		_, err = client.Logical().Write("pki/root/generate/internal", map[string]interface{}{
+			"common_name": "vaultapi test ca",
+			"ttl":         "24h",
+		})
+		require.NoError(t, err)
+	}
+
+	// allowing localhost, ip sans, and server and client
+	// certificates are the defaults of vault
+	pki := client.PKI("")
+	err = pki.CreateRole("test", PKIRole{
+		TTL:             time.Hour,
+		AllowedDomains:  []string{"example.com"},
+		AllowSubdomains: true,
+		KeyType:         "ec",
+		KeyBits:         256,
+	})
+	require.NoError(t, err)
+
+	return pki
+}
+
+func createCertificate(t *testing.T, template, parent *x509.Certificate, public crypto.PublicKey, signer crypto.Signer) string {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, public, signer)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// a pkiRequest is a request to issue a certificate received by pkiServer
+type pkiRequest struct {
+	method  string
+	path    string
+	options certificateOptionsJSON
+}
+
+// pkiServer pretends to be vault, issuing a certificate for commonName
+// signed by a local CA, and sending each request it receives on requests
+func pkiServer(t *testing.T, commonName string) (*httptest.Server, <-chan pkiRequest) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	ca := createCertificate(t, caTemplate, caTemplate, caKey.Public(), caKey)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	leaf := createCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, caTemplate, key.Public(), caKey)
+
+	var response signedCertificateWrapper
+	response.Data.Certificate = leaf
+	response.Data.IssuingCA = ca
+	response.Data.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	response.Data.PrivateKeyType = "ec"
+	response.Data.SerialNumber = "02"
+
+	requests := make(chan pkiRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := pkiRequest{method: r.Method, path: r.URL.Path}
+		if err := json.NewDecoder(r.Body).Decode(&request.options); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- request
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	return server, requests
+}
+
+func Test_PKI_IssueCertificate_parse(t *testing.T) {
+	server, requests := pkiServer(t, "www.example.com")
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	issued, err := client.PKI("/pki-int/").IssueCertificate("web", CertificateOptions{
+		CommonName: "www.example.com",
+		AltNames:   []string{"foo.example.com", "bar.example.com"},
+		TTL:        time.Hour,
+	})
+	require.NoError(t, err)
+
+	request := <-requests
+	require.Equal(t, http.MethodPost, request.method)
+	require.Equal(t, "/v1/pki-int/issue/web", request.path)
+	require.Equal(t, "www.example.com", request.options.CommonName)
+	require.Equal(t, "foo.example.com,bar.example.com", request.options.AltNames)
+	require.Equal(t, "3600s", request.options.TTL)
+
+	require.Equal(t, "www.example.com", issued.Certificate.Subject.CommonName)
+	require.Equal(t, "02", issued.SerialNumber)
+	require.Equal(t, "ec", issued.PrivateKeyType)
+	require.Equal(t, 1, len(issued.Chain))
+	require.Equal(t, "fake ca", issued.Chain[0].Subject.CommonName)
+	require.NoError(t, issued.Certificate.CheckSignatureFrom(issued.Chain[0]))
+	require.Equal(t, issued.Certificate.PublicKey, issued.PrivateKey.Public())
+
+	certificate := issued.TLSCertificate()
+	require.Equal(t, 2, len(certificate.Certificate))
+	require.Equal(t, issued.Certificate, certificate.Leaf)
+}
+
+func Test_PKIRole_JSON(t *testing.T) {
+	bs, err := json.Marshal(PKIRole{TTL: time.Hour, AllowedDomains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	var request map[string]interface{}
+	require.NoError(t, json.Unmarshal(bs, &request))
+	require.Equal(t, "3600s", request["ttl"])
+	require.NotContains(t, request, "max_ttl")
+	require.NotContains(t, request, "TTL")
+
+	// fields which vault defaults to true or non-empty are not sent unless set
+	for _, key := range []string{"allow_localhost", "enforce_hostnames", "allow_ip_sans", "server_flag", "client_flag", "require_cn", "key_usage"} {
+		require.NotContains(t, request, key)
+	}
+
+	// nor are the flags which vault defaults to false
+	for _, key := range []string{"allow_bare_domains", "allow_subdomains", "allow_glob_domains", "allow_any_name", "generate_lease", "no_store"} {
+		require.NotContains(t, request, key)
+	}
+
+	no := false
+	bs, err = json.Marshal(PKIRole{ClientFlag: &no, KeyUsage: []string{"DigitalSignature"}, AllowSubdomains: true})
+	require.NoError(t, err)
+	request = nil
+	require.NoError(t, json.Unmarshal(bs, &request))
+	require.Equal(t, false, request["client_flag"])
+	require.Equal(t, true, request["allow_subdomains"])
+	require.Equal(t, []interface{}{"DigitalSignature"}, request["key_usage"])
+
+	var role PKIRole
+	err = json.Unmarshal([]byte(`{"ttl": 3600, "max_ttl": 7200, "allowed_domains": ["example.com"], "server_flag": true}`), &role)
+	require.NoError(t, err)
+	yes := true
+	require.Equal(t, PKIRole{
+		TTL:            time.Hour,
+		MaxTTL:         2 * time.Hour,
+		AllowedDomains: []string{"example.com"},
+		ServerFlag:     &yes,
+	}, role)
+}
+
+func Test_PKI_DevVault(t *testing.T) {
+	pki := getPKI(t)
+
+	roles, err := pki.ListRoles()
+	require.NoError(t, err)
+	require.Contains(t, roles, "test")
+
+	role, err := pki.ReadRole("test")
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, role.TTL)
+	require.Equal(t, []string{"example.com"}, role.AllowedDomains)
+
+	issued, err := pki.IssueCertificate("test", CertificateOptions{
+		CommonName: "www.example.com",
+		IPSANs:     []string{"127.0.0.1"},
+		TTL:        10 * time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www.example.com", issued.Certificate.Subject.CommonName)
+
+	ca, err := pki.ReadCA()
+	require.NoError(t, err)
+	require.NoError(t, issued.Certificate.CheckSignatureFrom(ca))
+
+	read, err := pki.ReadCertificate(issued.SerialNumber)
+	require.NoError(t, err)
+	require.Equal(t, issued.Certificate.Raw, read.Raw)
+
+	serials, err := pki.ListCertificates()
+	require.NoError(t, err)
+	require.Contains(t, serials, issued.SerialNumber)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "csr.example.com"},
+	}, key)
+	require.NoError(t, err)
+
+	signed, err := pki.SignCSR("test", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), CertificateOptions{
+		CommonName: "csr.example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, key.Public(), signed.Certificate.PublicKey)
+
+	revoked, err := pki.RevokeCertificate(signed.SerialNumber)
+	require.NoError(t, err)
+	require.False(t, revoked.IsZero())
+
+	crl, err := pki.ReadCRL()
+	require.NoError(t, err)
+	require.NotEmpty(t, crl.TBSCertList.RevokedCertificates)
+
+	require.NoError(t, pki.Tidy(TidyOptions{TidyCertStore: true, TidyRevokedCerts: true, SafetyBuffer: time.Hour}))
+
+	require.NoError(t, pki.DeleteRole("test"))
+}
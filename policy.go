@@ -0,0 +1,521 @@
+package vaultapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The capabilities which may be granted on a path by a policy.
+//
+// More information about capabilities can be found here:
+// https://www.vaultproject.io/docs/concepts/policies.html#capabilities
+const (
+	CapabilityCreate = "create"
+	CapabilityRead   = "read"
+	CapabilityUpdate = "update"
+	CapabilityDelete = "delete"
+	CapabilityList   = "list"
+	CapabilitySudo   = "sudo"
+	CapabilityDeny   = "deny"
+)
+
+var knownCapabilities = map[string]bool{
+	CapabilityCreate: true,
+	CapabilityRead:   true,
+	CapabilityUpdate: true,
+	CapabilityDelete: true,
+	CapabilityList:   true,
+	CapabilitySudo:   true,
+	CapabilityDeny:   true,
+}
+
+// the capabilities implied by the deprecated policy = "..." syntax
+var policyShorthand = map[string][]string{
+	"deny":  {CapabilityDeny},
+	"read":  {CapabilityRead, CapabilityList},
+	"write": {CapabilityCreate, CapabilityRead, CapabilityUpdate, CapabilityDelete, CapabilityList},
+	"sudo":  {CapabilityCreate, CapabilityRead, CapabilityUpdate, CapabilityDelete, CapabilityList, CapabilitySudo},
+}
+
+// A Policy is the typed form of an ACL policy document.
+//
+// More information about policies can be found here:
+// https://www.vaultproject.io/docs/concepts/policies.html
+type Policy struct {
+	// Name is the name of the policy in vault. It is not
+	// part of the policy document itself.
+	Name string
+
+	// Paths contains each path rule in the order they are
+	// defined in the policy document.
+	Paths []PathRule
+}
+
+// A PathRule grants capabilities on paths matching Path, which may end in
+// a * glob and may contain + wildcards matching a single path segment.
+type PathRule struct {
+	Path         string
+	Capabilities []string
+
+	// Policy is the deprecated shorthand for capabilities, one
+	// of "deny", "read", "write", or "sudo".
+	Policy string
+
+	AllowedParameters  map[string][]interface{}
+	DeniedParameters   map[string][]interface{}
+	RequiredParameters []string
+	MinWrappingTTL     time.Duration
+	MaxWrappingTTL     time.Duration
+}
+
+// EffectiveCapabilities returns the capabilities granted by r, including
+// those implied by the deprecated Policy shorthand, in sorted order.
+func (r PathRule) EffectiveCapabilities() []string {
+	set := make(map[string]bool)
+	for _, capability := range r.Capabilities {
+		set[capability] = true
+	}
+	for _, capability := range policyShorthand[r.Policy] {
+		set[capability] = true
+	}
+
+	capabilities := make([]string, 0, len(set))
+	for capability := range set {
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// ParsePolicy parses a policy document, which may be in either
+// the HCL or JSON form.
+func ParsePolicy(document string) (Policy, error) {
+	object, err := parseDocument(document)
+	if err != nil {
+		return Policy{}, errors.Wrap(err, "failed to parse policy")
+	}
+
+	var policy Policy
+	for _, item := range object {
+		switch item.key {
+		case "name":
+			// allowed by vault, but ignored
+		case "path":
+			rules, err := decodePathRules(item)
+			if err != nil {
+				return Policy{}, errors.Wrap(err, "failed to parse policy")
+			}
+			policy.Paths = append(policy.Paths, rules...)
+		default:
+			return Policy{}, errors.Errorf("failed to parse policy: line %d: unsupported key %q", item.line, item.key)
+		}
+	}
+
+	return policy, nil
+}
+
+func decodePathRules(item hclItem) ([]PathRule, error) {
+	body, ok := item.value.(hclObject)
+	if !ok {
+		return nil, errors.Errorf("line %d: path must be a block", item.line)
+	}
+
+	switch len(item.labels) {
+	case 1:
+		// path "secret/foo" { ... }
+		rule, err := decodePathRule(item.labels[0], body)
+		if err != nil {
+			return nil, err
+		}
+		return []PathRule{rule}, nil
+	case 0:
+		// path = { "secret/foo" = { ... } }, or the JSON form
+		var rules []PathRule
+		for _, nested := range body {
+			rule, ok := nested.value.(hclObject)
+			if !ok || len(nested.labels) > 0 {
+				return nil, errors.Errorf("line %d: path %q must be a block", nested.line, nested.key)
+			}
+			decoded, err := decodePathRule(nested.key, rule)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, decoded)
+		}
+		return rules, nil
+	}
+
+	return nil, errors.Errorf("line %d: path block must have exactly one label", item.line)
+}
+
+func decodePathRule(path string, body hclObject) (PathRule, error) {
+	rule := PathRule{Path: path}
+	seen := make(map[string]bool, len(body))
+	for _, item := range body {
+		if seen[item.key] {
+			return PathRule{}, errors.Errorf("line %d: duplicate key %q in path %q", item.line, item.key, path)
+		}
+		seen[item.key] = true
+
+		// a JSON null is the same as leaving out the key
+		if item.value == nil {
+			continue
+		}
+
+		var err error
+		switch item.key {
+		case "capabilities":
+			rule.Capabilities, err = decodeStrings(item)
+		case "policy":
+			rule.Policy, err = decodeString(item)
+		case "allowed_parameters":
+			rule.AllowedParameters, err = decodeParameters(item)
+		case "denied_parameters":
+			rule.DeniedParameters, err = decodeParameters(item)
+		case "required_parameters":
+			rule.RequiredParameters, err = decodeStrings(item)
+		case "min_wrapping_ttl":
+			rule.MinWrappingTTL, err = decodeTTL(item)
+		case "max_wrapping_ttl":
+			rule.MaxWrappingTTL, err = decodeTTL(item)
+		default:
+			err = errors.Errorf("line %d: unsupported key %q in path %q", item.line, item.key, path)
+		}
+		if err != nil {
+			return PathRule{}, err
+		}
+	}
+	return rule, nil
+}
+
+func decodeString(item hclItem) (string, error) {
+	s, ok := item.value.(string)
+	if !ok {
+		return "", errors.Errorf("line %d: %s must be a string", item.line, item.key)
+	}
+	return s, nil
+}
+
+func decodeStrings(item hclItem) ([]string, error) {
+	list, ok := item.value.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("line %d: %s must be a list", item.line, item.key)
+	}
+	strs := make([]string, 0, len(list))
+	for _, element := range list {
+		s, ok := element.(string)
+		if !ok {
+			return nil, errors.Errorf("line %d: %s must contain only strings", item.line, item.key)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+func decodeParameters(item hclItem) (map[string][]interface{}, error) {
+	object, ok := item.value.(hclObject)
+	if !ok {
+		return nil, errors.Errorf("line %d: %s must be a block", item.line, item.key)
+	}
+	parameters := make(map[string][]interface{}, len(object))
+	for _, parameter := range object {
+		if _, exists := parameters[parameter.key]; exists {
+			return nil, errors.Errorf("line %d: duplicate parameter %q in %s", parameter.line, parameter.key, item.key)
+		}
+
+		// a JSON null allows any value, the same as an empty list
+		if parameter.value == nil {
+			parameters[parameter.key] = []interface{}{}
+			continue
+		}
+
+		values, ok := plainValue(parameter.value).([]interface{})
+		if !ok {
+			return nil, errors.Errorf("line %d: values of parameter %q must be a list", parameter.line, parameter.key)
+		}
+		parameters[parameter.key] = values
+	}
+	return parameters, nil
+}
+
+// plainValue converts the objects within value to maps, so that parameter
+// values contain only the types produced by encoding/json
+func plainValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case hclObject:
+		m := make(map[string]interface{}, len(value))
+		for _, item := range value {
+			m[item.key] = plainValue(item.value)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, 0, len(value))
+		for _, element := range value {
+			list = append(list, plainValue(element))
+		}
+		return list
+	}
+	return value
+}
+
+func decodeTTL(item hclItem) (time.Duration, error) {
+	switch value := item.value.(type) {
+	case int:
+		return time.Duration(value) * time.Second, nil
+	case string:
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second, nil
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errors.Errorf("line %d: invalid duration for %s: %q", item.line, item.key, value)
+		}
+		return ttl, nil
+	}
+	return 0, errors.Errorf("line %d: %s must be a duration", item.line, item.key)
+}
+
+// Validate checks p for mistakes that would cause vault to reject the
+// policy, or cause it to behave differently than intended.
+func (p Policy) Validate() error {
+	for _, rule := range p.Paths {
+		if err := rule.validate(); err != nil {
+			return errors.Wrapf(err, "invalid rule for path %q", rule.Path)
+		}
+	}
+	return nil
+}
+
+func (r PathRule) validate() error {
+	if r.Path == "" {
+		return errors.New("path must not be empty")
+	}
+
+	if index := strings.Index(r.Path, "*"); index >= 0 && index != len(r.Path)-1 {
+		return errors.New("glob * is only allowed at the end of a path")
+	}
+
+	for _, segment := range strings.Split(r.Path, "/") {
+		if strings.Contains(segment, "+") && segment != "+" {
+			return errors.New("wildcard + must be an entire path segment")
+		}
+	}
+
+	if r.Policy != "" {
+		if _, exists := policyShorthand[r.Policy]; !exists {
+			return errors.Errorf("unknown policy %q", r.Policy)
+		}
+	}
+
+	for _, capability := range r.Capabilities {
+		if !knownCapabilities[capability] {
+			return errors.Errorf("unknown capability %q", capability)
+		}
+	}
+
+	if r.MinWrappingTTL < 0 || r.MaxWrappingTTL < 0 {
+		return errors.New("wrapping ttl must not be negative")
+	}
+
+	if r.MaxWrappingTTL > 0 && r.MinWrappingTTL > r.MaxWrappingTTL {
+		return errors.New("min_wrapping_ttl must not exceed max_wrapping_ttl")
+	}
+
+	return nil
+}
+
+// HCL renders p as a policy document in the HCL form.
+func (p Policy) HCL() string {
+	var buf bytes.Buffer
+	for i, rule := range p.Paths {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "path %s {\n", strconv.Quote(rule.Path))
+		if len(rule.Capabilities) > 0 {
+			fmt.Fprintf(&buf, "  capabilities = %s\n", hclList(stringsToValues(rule.Capabilities)))
+		}
+		if rule.Policy != "" {
+			fmt.Fprintf(&buf, "  policy = %s\n", strconv.Quote(rule.Policy))
+		}
+		writeHCLParameters(&buf, "allowed_parameters", rule.AllowedParameters)
+		writeHCLParameters(&buf, "denied_parameters", rule.DeniedParameters)
+		if len(rule.RequiredParameters) > 0 {
+			fmt.Fprintf(&buf, "  required_parameters = %s\n", hclList(stringsToValues(rule.RequiredParameters)))
+		}
+		if rule.MinWrappingTTL > 0 {
+			fmt.Fprintf(&buf, "  min_wrapping_ttl = %q\n", rule.MinWrappingTTL.String())
+		}
+		if rule.MaxWrappingTTL > 0 {
+			fmt.Fprintf(&buf, "  max_wrapping_ttl = %q\n", rule.MaxWrappingTTL.String())
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
+
+func writeHCLParameters(buf *bytes.Buffer, key string, parameters map[string][]interface{}) {
+	if parameters == nil {
+		return
+	}
+	fmt.Fprintf(buf, "  %s = {\n", key)
+	for _, name := range sortedKeys(parameters) {
+		fmt.Fprintf(buf, "    %s = %s\n", strconv.Quote(name), hclList(parameters[name]))
+	}
+	buf.WriteString("  }\n")
+}
+
+func sortedKeys(parameters map[string][]interface{}) []string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringsToValues(strs []string) []interface{} {
+	values := make([]interface{}, 0, len(strs))
+	for _, s := range strs {
+		values = append(values, s)
+	}
+	return values
+}
+
+func hclList(values []interface{}) string {
+	rendered := make([]string, 0, len(values))
+	for _, value := range values {
+		rendered = append(rendered, hclValueString(value))
+	}
+	return "[" + strings.Join(rendered, ", ") + "]"
+}
+
+// hclValueString renders value as an HCL value, where numbers are never
+// rendered with an exponent, so that integers are not parsed as floats
+func hclValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		// keep a decimal point, so that the value is parsed back as a float
+		f := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(f, ".") {
+			f += ".0"
+		}
+		return f
+	case []interface{}:
+		return hclList(v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(keys))
+		for _, key := range keys {
+			items = append(items, strconv.Quote(key)+" = "+hclValueString(v[key]))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+
+	// anything else is rendered as JSON, which is valid HCL for
+	// the other numeric types; note HCL has no equivalent of null
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return strconv.Quote(fmt.Sprint(value))
+	}
+	return string(bs)
+}
+
+type pathRuleJSON struct {
+	Capabilities       []string                 `json:"capabilities,omitempty"`
+	Policy             string                   `json:"policy,omitempty"`
+	AllowedParameters  map[string][]interface{} `json:"allowed_parameters,omitempty"`
+	DeniedParameters   map[string][]interface{} `json:"denied_parameters,omitempty"`
+	RequiredParameters []string                 `json:"required_parameters,omitempty"`
+	MinWrappingTTL     string                   `json:"min_wrapping_ttl,omitempty"`
+	MaxWrappingTTL     string                   `json:"max_wrapping_ttl,omitempty"`
+}
+
+// JSON renders p as a policy document in the JSON form, preserving the
+// order of the rules. Unlike the HCL form, the JSON form cannot contain
+// more than one rule for the same path.
+func (p Policy) JSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"path":{`)
+
+	seen := make(map[string]bool, len(p.Paths))
+	for i, rule := range p.Paths {
+		if seen[rule.Path] {
+			return nil, errors.Errorf("multiple rules for path %q", rule.Path)
+		}
+		seen[rule.Path] = true
+
+		key, err := json.Marshal(rule.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(pathRuleJSON{
+			Capabilities:       rule.Capabilities,
+			Policy:             rule.Policy,
+			AllowedParameters:  rule.AllowedParameters,
+			DeniedParameters:   rule.DeniedParameters,
+			RequiredParameters: rule.RequiredParameters,
+			MinWrappingTTL:     durationString(rule.MinWrappingTTL),
+			MaxWrappingTTL:     durationString(rule.MaxWrappingTTL),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create json for path %q", rule.Path)
+		}
+
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}}")
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+func (c *client) GetPolicyParsed(name string) (Policy, error) {
+	content, err := c.GetPolicy(name)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	policy, err := ParsePolicy(content)
+	if err != nil {
+		return Policy{}, errors.Wrapf(err, "failed to parse policy %q", name)
+	}
+	policy.Name = name
+
+	return policy, nil
+}
+
+func (c *client) SetPolicyTyped(name string, policy Policy) error {
+	if err := policy.Validate(); err != nil {
+		return errors.Wrapf(err, "refusing to set invalid policy %q", name)
+	}
+	return c.SetPolicy(name, policy.HCL())
+}
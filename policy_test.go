@@ -0,0 +1,242 @@
+package vaultapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const pol2 = `
+# manage secrets of the foo team
+path "secret/foo/*" {
+  capabilities = ["create", "read", "update"]
+  allowed_parameters = {
+    "region" = ["us-east", "us-west"]
+    "*" = []
+  }
+  denied_parameters = {
+    "admin" = []
+  }
+  required_parameters = ["region"]
+  min_wrapping_ttl = "1m"
+  max_wrapping_ttl = 3600
+}
+
+/* the old syntax is still accepted */
+path "secret/+/public" {
+  policy = "read"
+}
+`
+
+const pol2JSON = `{
+  "path": {
+    "secret/foo/*": {
+      "capabilities": ["create", "read", "update"],
+      "allowed_parameters": {"region": ["us-east", "us-west"], "*": []},
+      "denied_parameters": {"admin": []},
+      "required_parameters": ["region"],
+      "min_wrapping_ttl": "1m",
+      "max_wrapping_ttl": "1h"
+    },
+    "secret/+/public": {
+      "policy": "read"
+    }
+  }
+}`
+
+func Test_ParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy(pol2)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(policy.Paths))
+
+	foo := policy.Paths[0]
+	require.Equal(t, "secret/foo/*", foo.Path)
+	require.Equal(t, []string{"create", "read", "update"}, foo.Capabilities)
+	require.Equal(t, []interface{}{"us-east", "us-west"}, foo.AllowedParameters["region"])
+	require.Equal(t, []interface{}{}, foo.AllowedParameters["*"])
+	require.Equal(t, []interface{}{}, foo.DeniedParameters["admin"])
+	require.Equal(t, []string{"region"}, foo.RequiredParameters)
+	require.Equal(t, time.Minute, foo.MinWrappingTTL)
+	require.Equal(t, time.Hour, foo.MaxWrappingTTL)
+
+	public := policy.Paths[1]
+	require.Equal(t, "secret/+/public", public.Path)
+	require.Equal(t, "read", public.Policy)
+	require.Equal(t, []string{"list", "read"}, public.EffectiveCapabilities())
+
+	require.NoError(t, policy.Validate())
+}
+
+func Test_ParsePolicy_JSON(t *testing.T) {
+	fromHCL, err := ParsePolicy(pol2)
+	require.NoError(t, err)
+
+	fromJSON, err := ParsePolicy(pol2JSON)
+	require.NoError(t, err)
+
+	require.Equal(t, fromHCL, fromJSON)
+}
+
+func Test_ParsePolicy_JSON_escapes(t *testing.T) {
+	policy, err := ParsePolicy(`{
+  "path": {
+    "secret\/foo\/*": {
+      "capabilities": ["read", "list"],
+      "policy": null,
+      "allowed_parameters": {"region": null, "zone": ["us-east-1\u0061"]},
+      "denied_parameters": null,
+      "min_wrapping_ttl": null
+    }
+  }
+}`)
+	require.NoError(t, err)
+	require.Equal(t, []PathRule{{
+		Path:              "secret/foo/*",
+		Capabilities:      []string{"read", "list"},
+		AllowedParameters: map[string][]interface{}{"region": {}, "zone": {"us-east-1a"}},
+	}}, policy.Paths)
+}
+
+func Test_ParsePolicy_heredoc(t *testing.T) {
+	policy, err := ParsePolicy(`
+path "sys/config/ui/headers/*" {
+  capabilities = ["update"]
+  allowed_parameters = {
+    "values" = [<<EOT
+Content-Security-Policy
+EOT
+    ]
+  }
+}
+`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(policy.Paths))
+	require.Equal(t, []interface{}{"Content-Security-Policy\n"}, policy.Paths[0].AllowedParameters["values"])
+}
+
+func Test_ParsePolicy_duplicates(t *testing.T) {
+	// separate rules for the same path are merged by vault
+	policy, err := ParsePolicy(`
+path "secret/*" { capabilities = ["read"] }
+path "secret/*" { capabilities = ["list"] }
+`)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(policy.Paths))
+
+	for _, document := range []string{
+		`path "secret/*" { capabilities = ["read"] capabilities = ["list"] }`,
+		`path "secret/*" { allowed_parameters = { "a" = [] "a" = ["b"] } }`,
+		`{"path": {"secret/*": {"capabilities": ["read"], "capabilities": ["list"]}}}`,
+	} {
+		_, err := ParsePolicy(document)
+		require.Error(t, err, "document: %s", document)
+	}
+}
+
+func Test_ParsePolicy_errors(t *testing.T) {
+	for _, document := range []string{
+		`path "secret/*" { capabilities = ["read"`,
+		`path "secret/*" { capabilities = "read" }`,
+		`path "secret/*" { colour = "blue" }`,
+		`path "secret/*" { min_wrapping_ttl = "soon" }`,
+		`path = "secret/*"`,
+		`paths "secret/*" {}`,
+		`path "secret/*" { capabilities = ["read"] } /* unterminated`,
+		`{"path": {"secret/*": {"capabilities": ["read"]}}} {}`,
+		`{"path": {"secret/*": {"capabilities": ["read",]}}}`,
+		`{"path": {"secret/*": {"capabilities": [null]}}}`,
+	} {
+		_, err := ParsePolicy(document)
+		require.Error(t, err, "document: %s", document)
+	}
+}
+
+func Test_Policy_roundTrip(t *testing.T) {
+	policy, err := ParsePolicy(pol2)
+	require.NoError(t, err)
+
+	fromHCL, err := ParsePolicy(policy.HCL())
+	require.NoError(t, err)
+	require.Equal(t, policy, fromHCL)
+
+	bs, err := policy.JSON()
+	require.NoError(t, err)
+
+	fromJSON, err := ParsePolicy(string(bs))
+	require.NoError(t, err)
+	require.Equal(t, policy, fromJSON)
+}
+
+func Test_Policy_JSON_duplicates(t *testing.T) {
+	policy := Policy{Paths: []PathRule{
+		{Path: "secret/*", Capabilities: []string{"read"}},
+		{Path: "secret/*", Capabilities: []string{"list"}},
+	}}
+	_, err := policy.JSON()
+	require.Error(t, err)
+}
+
+func Test_Policy_Validate(t *testing.T) {
+	for _, rule := range []PathRule{
+		{Path: "", Capabilities: []string{"read"}},
+		{Path: "secret/*/foo", Capabilities: []string{"read"}},
+		{Path: "secret/foo+/bar", Capabilities: []string{"read"}},
+		{Path: "secret/foo", Capabilities: []string{"write"}},
+		{Path: "secret/foo", Policy: "admin"},
+		{Path: "secret/foo", Capabilities: []string{"read"}, MinWrappingTTL: time.Hour, MaxWrappingTTL: time.Minute},
+	} {
+		policy := Policy{Paths: []PathRule{rule}}
+		require.Error(t, policy.Validate(), "rule: %v", rule)
+	}
+
+	// vault accepts a rule without capabilities, which has no effect
+	policy, err := ParsePolicy(`path "secret/foo" { capabilities = [] }`)
+	require.NoError(t, err)
+	require.NoError(t, policy.Validate())
+}
+
+func Test_Policy_HCL_values(t *testing.T) {
+	policy, err := ParsePolicy(`{"path": {"secret/*": {
+		"capabilities": ["create"],
+		"allowed_parameters": {
+			"big": [1000000000000000000, 12345678901234567890, 0.5],
+			"flag": [true],
+			"nested": [["a", "b"], {"key": "value", "other": [1, "two"]}],
+			"quoted": ["say \"hi\"\n"]
+		}
+	}}}`)
+	require.NoError(t, err)
+
+	document := policy.HCL()
+	require.Contains(t, document, `"big" = [1000000000000000000, 12345678901234567000.0, 0.5]`)
+	require.Contains(t, document, `"nested" = [["a", "b"], {"key" = "value", "other" = [1, "two"]}]`)
+	require.NotContains(t, document, "e+")
+
+	fromHCL, err := ParsePolicy(document)
+	require.NoError(t, err)
+	require.Equal(t, policy, fromHCL)
+	require.Equal(t, map[string]interface{}{"key": "value", "other": []interface{}{1, "two"}},
+		fromHCL.Paths[0].AllowedParameters["nested"][1])
+}
+
+func Test_Client_PolicyTyped(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	policy, err := ParsePolicy(pol2)
+	require.NoError(t, err)
+
+	err = client.SetPolicyTyped("typed", policy)
+	require.NoError(t, err)
+
+	parsed, err := client.GetPolicyParsed("typed")
+	require.NoError(t, err)
+	require.Equal(t, "typed", parsed.Name)
+	require.Equal(t, policy.Paths, parsed.Paths)
+
+	err = client.SetPolicyTyped("typed", Policy{Paths: []PathRule{{Path: "secret/*"}}})
+	require.Error(t, err)
+
+	err = client.DeletePolicy("typed")
+	require.NoError(t, err)
+}
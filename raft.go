@@ -0,0 +1,243 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A RaftConfiguration describes the membership of a vault cluster
+// which uses integrated storage.
+//
+// More information about integrated storage can be found here:
+// https://www.vaultproject.io/api/system/storage/raft.html
+type RaftConfiguration struct {
+	Index   int          `json:"index"`
+	Servers []RaftServer `json:"servers"`
+}
+
+// A RaftServer is a member of a RaftConfiguration.
+type RaftServer struct {
+	NodeID          string `json:"node_id"`
+	Address         string `json:"address"`
+	Leader          bool   `json:"leader"`
+	ProtocolVersion string `json:"protocol_version"`
+	Voter           bool   `json:"voter"`
+}
+
+// Leader returns the member of rc that is currently the
+// leader, if there is one.
+func (rc RaftConfiguration) Leader() (RaftServer, bool) {
+	for _, server := range rc.Servers {
+		if server.Leader {
+			return server, true
+		}
+	}
+	return RaftServer{}, false
+}
+
+type raftConfigurationWrapper struct {
+	Data struct {
+		Config RaftConfiguration `json:"config"`
+	} `json:"data"`
+}
+
+func (c *client) RaftConfiguration() (RaftConfiguration, error) {
+	var wrapper raftConfigurationWrapper
+	if err := c.get("/v1/sys/storage/raft/configuration", &wrapper); err != nil {
+		return RaftConfiguration{}, errors.Wrap(err, "failed to read raft configuration")
+	}
+	return wrapper.Data.Config, nil
+}
+
+func (c *client) RaftRemovePeer(id string) error {
+	bs, err := json.Marshal(struct {
+		ID string `json:"server_id"`
+	}{ID: id})
+	if err != nil {
+		return err
+	}
+
+	if err := c.post("/v1/sys/storage/raft/remove-peer", string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to remove raft peer %q", id)
+	}
+
+	return nil
+}
+
+// RaftJoinOptions are used to configure how a vault server joins
+// an existing raft cluster.
+type RaftJoinOptions struct {
+	LeaderAPIAddr    string `json:"leader_api_addr"`
+	LeaderCACert     string `json:"leader_ca_cert,omitempty"`
+	LeaderClientCert string `json:"leader_client_cert,omitempty"`
+	LeaderClientKey  string `json:"leader_client_key,omitempty"`
+	Retry            bool   `json:"retry,omitempty"`
+	NonVoter         bool   `json:"non_voter,omitempty"`
+}
+
+// RaftJoin causes the vault server to join the raft cluster led by the
+// server at opts.LeaderAPIAddr. Note that the request must be made to the
+// server that is joining, which can be done with a Client configured for
+// only that server.
+func (c *client) RaftJoin(opts RaftJoinOptions) (bool, error) {
+	bs, err := json.Marshal(opts)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create json for raft join")
+	}
+
+	var response struct {
+		Joined bool `json:"joined"`
+	}
+	if err := c.post("/v1/sys/storage/raft/join", string(bs), &response); err != nil {
+		return false, errors.Wrapf(err, "failed to join raft cluster at %q", opts.LeaderAPIAddr)
+	}
+
+	return response.Joined, nil
+}
+
+// An AutopilotState describes the health of a raft cluster,
+// as determined by autopilot.
+type AutopilotState struct {
+	Healthy          bool                       `json:"healthy"`
+	FailureTolerance int                        `json:"failure_tolerance"`
+	Leader           string                     `json:"leader"`
+	Voters           []string                   `json:"voters"`
+	Servers          map[string]AutopilotServer `json:"servers"`
+}
+
+// An AutopilotServer describes the health of one member of
+// a raft cluster, as determined by autopilot.
+type AutopilotServer struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Address     string    `json:"address"`
+	NodeStatus  string    `json:"node_status"`
+	LastContact string    `json:"last_contact"`
+	LastTerm    int       `json:"last_term"`
+	LastIndex   int       `json:"last_index"`
+	Healthy     bool      `json:"healthy"`
+	StableSince time.Time `json:"stable_since"`
+	Status      string    `json:"status"`
+	Version     string    `json:"version"`
+}
+
+type autopilotStateWrapper struct {
+	Data AutopilotState `json:"data"`
+}
+
+func (c *client) RaftAutopilotState() (AutopilotState, error) {
+	var wrapper autopilotStateWrapper
+	if err := c.get("/v1/sys/storage/raft/autopilot/state", &wrapper); err != nil {
+		return AutopilotState{}, errors.Wrap(err, "failed to read autopilot state")
+	}
+	return wrapper.Data, nil
+}
+
+// An AutopilotConfig configures how autopilot manages the members of a
+// raft cluster. When used to set the configuration, durations and counts
+// left as their zero value, and CleanupDeadServers left nil, are not sent
+// to vault, and therefore retain whatever value vault already has for them.
+type AutopilotConfig struct {
+	CleanupDeadServers             *bool
+	LastContactThreshold           time.Duration
+	DeadServerLastContactThreshold time.Duration
+	MaxTrailingLogs                int
+	MinQuorum                      int
+	ServerStabilizationTime        time.Duration
+}
+
+// vault both accepts and reports durations as duration strings
+type autopilotConfigJSON struct {
+	CleanupDeadServers             *bool  `json:"cleanup_dead_servers,omitempty"`
+	LastContactThreshold           string `json:"last_contact_threshold,omitempty"`
+	DeadServerLastContactThreshold string `json:"dead_server_last_contact_threshold,omitempty"`
+	MaxTrailingLogs                int    `json:"max_trailing_logs,omitempty"`
+	MinQuorum                      int    `json:"min_quorum,omitempty"`
+	ServerStabilizationTime        string `json:"server_stabilization_time,omitempty"`
+}
+
+func durationString(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// MarshalJSON encodes ac in the form expected by vault.
+func (ac AutopilotConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(autopilotConfigJSON{
+		CleanupDeadServers:             ac.CleanupDeadServers,
+		LastContactThreshold:           durationString(ac.LastContactThreshold),
+		DeadServerLastContactThreshold: durationString(ac.DeadServerLastContactThreshold),
+		MaxTrailingLogs:                ac.MaxTrailingLogs,
+		MinQuorum:                      ac.MinQuorum,
+		ServerStabilizationTime:        durationString(ac.ServerStabilizationTime),
+	})
+}
+
+// UnmarshalJSON decodes ac from the form returned by vault.
+func (ac *AutopilotConfig) UnmarshalJSON(bs []byte) error {
+	var response autopilotConfigJSON
+	if err := json.Unmarshal(bs, &response); err != nil {
+		return err
+	}
+
+	lastContactThreshold, err := parseDuration(response.LastContactThreshold)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse last_contact_threshold")
+	}
+
+	deadServerLastContactThreshold, err := parseDuration(response.DeadServerLastContactThreshold)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse dead_server_last_contact_threshold")
+	}
+
+	serverStabilizationTime, err := parseDuration(response.ServerStabilizationTime)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse server_stabilization_time")
+	}
+
+	*ac = AutopilotConfig{
+		CleanupDeadServers:             response.CleanupDeadServers,
+		LastContactThreshold:           lastContactThreshold,
+		DeadServerLastContactThreshold: deadServerLastContactThreshold,
+		MaxTrailingLogs:                response.MaxTrailingLogs,
+		MinQuorum:                      response.MinQuorum,
+		ServerStabilizationTime:        serverStabilizationTime,
+	}
+	return nil
+}
+
+type autopilotConfigWrapper struct {
+	Data AutopilotConfig `json:"data"`
+}
+
+func (c *client) RaftAutopilotConfig() (AutopilotConfig, error) {
+	var wrapper autopilotConfigWrapper
+	if err := c.get("/v1/sys/storage/raft/autopilot/configuration", &wrapper); err != nil {
+		return AutopilotConfig{}, errors.Wrap(err, "failed to read autopilot configuration")
+	}
+	return wrapper.Data, nil
+}
+
+func (c *client) SetRaftAutopilotConfig(config AutopilotConfig) error {
+	bs, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to create json for autopilot configuration")
+	}
+
+	if err := c.post("/v1/sys/storage/raft/autopilot/configuration", string(bs), nil); err != nil {
+		return errors.Wrap(err, "failed to set autopilot configuration")
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Raft_Configuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/storage/raft/configuration":
+			_, _ = w.Write([]byte(`{"data": {"config": {
+				"index": 10,
+				"servers": [{"node_id": "raft1", "address": "10.0.0.1:8201", "leader": true, "voter": true}]
+			}}}`))
+		case "/v1/sys/storage/raft/autopilot/state":
+			// like vault when it does not use raft storage
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"errors": ["raft storage is not in use"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	config, err := client.RaftConfiguration()
+	require.NoError(t, err)
+	require.Equal(t, RaftConfiguration{
+		Index:   10,
+		Servers: []RaftServer{{NodeID: "raft1", Address: "10.0.0.1:8201", Leader: true, Voter: true}},
+	}, config)
+
+	_, err = client.RaftAutopilotState()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read autopilot state")
+	require.Contains(t, err.Error(), "all attempts for GET request failed")
+
+	_, err = client.RaftAutopilotConfig()
+	require.Equal(t, ErrPathNotFound, errors.Cause(err))
+}
+
+func Test_Raft_Leader(t *testing.T) {
+	var config RaftConfiguration
+	err := json.Unmarshal([]byte(`{
+		"index": 10,
+		"servers": [
+			{"node_id": "raft1", "address": "10.0.0.1:8201", "leader": false, "voter": true},
+			{"node_id": "raft2", "address": "10.0.0.2:8201", "leader": true, "voter": true}
+		]
+	}`), &config)
+	require.NoError(t, err)
+
+	leader, exists := config.Leader()
+	require.True(t, exists)
+	require.Equal(t, "raft2", leader.NodeID)
+
+	_, exists = RaftConfiguration{}.Leader()
+	require.False(t, exists)
+}
+
+func Test_Raft_AutopilotConfigJSON(t *testing.T) {
+	var config AutopilotConfig
+	err := json.Unmarshal([]byte(`{
+		"cleanup_dead_servers": true,
+		"last_contact_threshold": "10s",
+		"dead_server_last_contact_threshold": "24h0m0s",
+		"max_trailing_logs": 1000,
+		"min_quorum": 3,
+		"server_stabilization_time": "10s"
+	}`), &config)
+	require.NoError(t, err)
+	yes := true
+	require.Equal(t, AutopilotConfig{
+		CleanupDeadServers:             &yes,
+		LastContactThreshold:           10 * time.Second,
+		DeadServerLastContactThreshold: 24 * time.Hour,
+		MaxTrailingLogs:                1000,
+		MinQuorum:                      3,
+		ServerStabilizationTime:        10 * time.Second,
+	}, config)
+
+	bs, err := json.Marshal(AutopilotConfig{
+		CleanupDeadServers: &yes,
+		MinQuorum:          5,
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"cleanup_dead_servers": true, "min_quorum": 5}`, string(bs))
+
+	// leaving cleanup_dead_servers unset retains its value in vault
+	bs, err = json.Marshal(AutopilotConfig{MinQuorum: 5})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"min_quorum": 5}`, string(bs))
+
+	no := false
+	bs, err = json.Marshal(AutopilotConfig{CleanupDeadServers: &no})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"cleanup_dead_servers": false}`, string(bs))
+}
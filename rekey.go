@@ -0,0 +1,149 @@
+package vaultapi
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// RekeyOptions are used to configure the new key shares created
+// by rekeying vault.
+//
+// More information about rekeying can be found here:
+// https://www.vaultproject.io/api/system/rekey.html
+type RekeyOptions struct {
+	SecretShares    int      `json:"secret_shares"`
+	SecretThreshold int      `json:"secret_threshold"`
+	PGPKeys         []string `json:"pgp_keys,omitempty"`
+	Backup          bool     `json:"backup,omitempty"`
+}
+
+// A RekeyStatus is returned upon requesting the progress of a rekey
+// operation, and after providing each key share. Once the threshold of key
+// shares have been provided, Complete is set and Keys contains the newly
+// created key shares.
+type RekeyStatus struct {
+	Started         bool     `json:"started"`
+	Nonce           string   `json:"nonce"`
+	Threshold       int      `json:"t"`
+	Shares          int      `json:"n"`
+	Progress        int      `json:"progress"`
+	Required        int      `json:"required"`
+	PGPFingerprints []string `json:"pgp_fingerprints"`
+	Backup          bool     `json:"backup"`
+	Complete        bool     `json:"complete"`
+	Keys            []string `json:"keys"`
+	KeysBase64      []string `json:"keys_base64"`
+}
+
+func (c *client) RekeyStatus() (RekeyStatus, error) {
+	var status RekeyStatus
+	if err := c.get("/v1/sys/rekey/init", &status); err != nil {
+		return RekeyStatus{}, errors.Wrap(err, "failed to read rekey status")
+	}
+	return status, nil
+}
+
+func (c *client) RekeyInit(opts RekeyOptions) (RekeyStatus, error) {
+	bs, err := json.Marshal(opts)
+	if err != nil {
+		return RekeyStatus{}, errors.Wrap(err, "failed to create json for rekey init")
+	}
+
+	var status RekeyStatus
+	if err := c.post("/v1/sys/rekey/init", string(bs), &status); err != nil {
+		return RekeyStatus{}, errors.Wrap(err, "failed to start rekey")
+	}
+
+	return status, nil
+}
+
+type keyShare struct {
+	Key   string `json:"key"`
+	Nonce string `json:"nonce"`
+}
+
+func (c *client) RekeyUpdate(key, nonce string) (RekeyStatus, error) {
+	bs, err := json.Marshal(keyShare{Key: key, Nonce: nonce})
+	if err != nil {
+		// do not provide key anywhere
+		return RekeyStatus{}, errors.New("failed to create json for rekey update")
+	}
+
+	// do not log the response, which contains the new key shares
+	var status RekeyStatus
+	if err := c.post("/v1/sys/rekey/update", string(bs), &status); err != nil {
+		// do not provide key anywhere
+		return RekeyStatus{}, errors.Wrapf(err, "failed to provide key share for rekey %q", nonce)
+	}
+
+	return status, nil
+}
+
+func (c *client) RekeyCancel() error {
+	if err := c.delete("/v1/sys/rekey/init"); err != nil {
+		return errors.Wrap(err, "failed to cancel rekey")
+	}
+	return nil
+}
+
+// A RekeyProcess keeps track of an in-progress rekey operation, so that
+// key shares can be provided as they are collected from key holders.
+type RekeyProcess struct {
+	sys    Sys
+	status RekeyStatus
+}
+
+// StartRekey begins a new rekey operation using opts.
+func StartRekey(sys Sys, opts RekeyOptions) (*RekeyProcess, error) {
+	status, err := sys.RekeyInit(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &RekeyProcess{sys: sys, status: status}, nil
+}
+
+// ResumeRekey continues an existing rekey operation, such as
+// one that was started by a different process.
+func ResumeRekey(sys Sys) (*RekeyProcess, error) {
+	status, err := sys.RekeyStatus()
+	if err != nil {
+		return nil, err
+	}
+	if !status.Started {
+		return nil, errors.New("no rekey operation in progress")
+	}
+	return &RekeyProcess{sys: sys, status: status}, nil
+}
+
+// Status returns the most recently known status of the rekey operation.
+func (r *RekeyProcess) Status() RekeyStatus {
+	return r.status
+}
+
+// Submit provides one existing key share towards the rekey operation. Once
+// the returned status is Complete, it contains the new key shares.
+func (r *RekeyProcess) Submit(key string) (RekeyStatus, error) {
+	if r.status.Complete {
+		return r.status, errors.New("rekey operation is already complete")
+	}
+
+	status, err := r.sys.RekeyUpdate(key, r.status.Nonce)
+	if err != nil {
+		return r.status, err
+	}
+
+	// the nonce is omitted from some responses
+	if status.Nonce == "" {
+		status.Nonce = r.status.Nonce
+	}
+	r.status = status
+
+	return status, nil
+}
+
+// Cancel aborts the rekey operation, discarding any key
+// shares provided so far.
+func (r *RekeyProcess) Cancel() error {
+	return r.sys.RekeyCancel()
+}
@@ -0,0 +1,42 @@
+package vaultapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Rekey_StartCancel(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	status, err := client.RekeyStatus()
+	require.NoError(t, err)
+	require.False(t, status.Started)
+
+	rekey, err := StartRekey(client, RekeyOptions{
+		SecretShares:    3,
+		SecretThreshold: 2,
+	})
+	require.NoError(t, err)
+	require.True(t, rekey.Status().Started)
+	require.NotEmpty(t, rekey.Status().Nonce)
+	require.Equal(t, 3, rekey.Status().Shares)
+	require.Equal(t, 2, rekey.Status().Threshold)
+
+	resumed, err := ResumeRekey(client)
+	require.NoError(t, err)
+	require.Equal(t, rekey.Status().Nonce, resumed.Status().Nonce)
+
+	// not a valid key share of the dev vault
+	_, err = rekey.Submit("aW52YWxpZCBrZXkgc2hhcmUgZm9yIHRlc3Rpbmc=")
+	require.Error(t, err)
+
+	require.NoError(t, rekey.Cancel())
+
+	status, err = client.RekeyStatus()
+	require.NoError(t, err)
+	require.False(t, status.Started)
+
+	_, err = ResumeRekey(client)
+	require.Error(t, err)
+}
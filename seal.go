@@ -0,0 +1,142 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// InitOptions are used to configure the initialization of a new vault.
+//
+// More information about the initialization options can be found here:
+// https://www.vaultproject.io/api/system/init.html
+type InitOptions struct {
+	SecretShares      int      `json:"secret_shares"`
+	SecretThreshold   int      `json:"secret_threshold"`
+	PGPKeys           []string `json:"pgp_keys,omitempty"`
+	RootTokenPGPKey   string   `json:"root_token_pgp_key,omitempty"`
+	StoredShares      int      `json:"stored_shares,omitempty"`
+	RecoveryShares    int      `json:"recovery_shares,omitempty"`
+	RecoveryThreshold int      `json:"recovery_threshold,omitempty"`
+	RecoveryPGPKeys   []string `json:"recovery_pgp_keys,omitempty"`
+}
+
+// An InitResponse contains the unseal keys and initial root token created
+// upon initializing vault. This is the only time these values are made
+// available, and they must be kept somewhere safe.
+type InitResponse struct {
+	Keys               []string `json:"keys"`
+	KeysBase64         []string `json:"keys_base64"`
+	RecoveryKeys       []string `json:"recovery_keys"`
+	RecoveryKeysBase64 []string `json:"recovery_keys_base64"`
+	RootToken          string   `json:"root_token"`
+}
+
+type initStatus struct {
+	Initialized bool `json:"initialized"`
+}
+
+// InitStatus reports whether the first of the Client's servers has been
+// initialized. As with the other init and unseal operations, the request
+// is made without a token, since vault serves it before any token exists,
+// and is never retried against the other servers.
+func (c *client) InitStatus() (bool, error) {
+	return c.InitStatusServer(c.opts.Servers[0])
+}
+
+// InitStatusServer is InitStatus for the server at address.
+func (c *client) InitStatusServer(address string) (bool, error) {
+	var status initStatus
+	if err := c.unauthenticated(http.MethodGet, address, "/v1/sys/init", "", &status); err != nil {
+		return false, errors.Wrapf(err, "failed to read init status of %q", address)
+	}
+	return status.Initialized, nil
+}
+
+// Init initializes the first of the Client's servers. Each server of
+// an HA cluster need not be initialized, only unsealed.
+func (c *client) Init(opts InitOptions) (InitResponse, error) {
+	return c.InitServer(c.opts.Servers[0], opts)
+}
+
+// InitServer is Init for the server at address.
+func (c *client) InitServer(address string, opts InitOptions) (InitResponse, error) {
+	bs, err := json.Marshal(opts)
+	if err != nil {
+		return InitResponse{}, errors.Wrap(err, "failed to create json for init")
+	}
+
+	// do not log the response, which contains the unseal keys and root token
+	var response InitResponse
+	if err := c.unauthenticated(http.MethodPost, address, "/v1/sys/init", string(bs), &response); err != nil {
+		return InitResponse{}, errors.Wrapf(err, "failed to initialize %q", address)
+	}
+
+	return response, nil
+}
+
+// Seal seals the first of the Client's servers. Unlike the other
+// operations here, sealing requires a token with sudo capability.
+func (c *client) Seal() error {
+	return c.SealServer(c.opts.Servers[0])
+}
+
+// SealServer is Seal for the server at address.
+func (c *client) SealServer(address string) error {
+	if err := c.singlePut(address, "/v1/sys/seal", ""); err != nil {
+		return errors.Wrapf(err, "failed to seal %q", address)
+	}
+	return nil
+}
+
+type unsealRequest struct {
+	Key   string `json:"key,omitempty"`
+	Reset bool   `json:"reset,omitempty"`
+}
+
+// Unseal provides one key share towards unsealing the first of the Client's
+// servers. Once the threshold number of key shares have been provided, the
+// server becomes unsealed. In an HA cluster each server must be unsealed,
+// which can be done with UnsealServer.
+func (c *client) Unseal(key string) (SealStatus, error) {
+	return c.UnsealServer(c.opts.Servers[0], key)
+}
+
+// UnsealServer is Unseal for the server at address.
+func (c *client) UnsealServer(address, key string) (SealStatus, error) {
+	bs, err := json.Marshal(unsealRequest{Key: key})
+	if err != nil {
+		// do not provide key anywhere
+		return SealStatus{}, errors.New("failed to create json for unseal")
+	}
+
+	var status SealStatus
+	if err := c.unauthenticated(http.MethodPost, address, "/v1/sys/unseal", string(bs), &status); err != nil {
+		// do not provide key anywhere
+		return SealStatus{}, errors.Wrapf(err, "failed to unseal %q", address)
+	}
+
+	return status, nil
+}
+
+// UnsealReset discards any key shares provided so far to the first of
+// the Client's servers, restarting its unseal process.
+func (c *client) UnsealReset() (SealStatus, error) {
+	return c.UnsealResetServer(c.opts.Servers[0])
+}
+
+// UnsealResetServer is UnsealReset for the server at address.
+func (c *client) UnsealResetServer(address string) (SealStatus, error) {
+	bs, err := json.Marshal(unsealRequest{Reset: true})
+	if err != nil {
+		return SealStatus{}, err
+	}
+
+	var status SealStatus
+	if err := c.unauthenticated(http.MethodPost, address, "/v1/sys/unseal", string(bs), &status); err != nil {
+		return SealStatus{}, errors.Wrapf(err, "failed to reset unseal of %q", address)
+	}
+
+	return status, nil
+}
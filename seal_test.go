@@ -0,0 +1,112 @@
+package vaultapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Seal_InitStatus(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	initialized, err := client.InitStatus()
+	require.NoError(t, err)
+	require.True(t, initialized)
+
+	// the dev vault is already initialized
+	_, err = client.Init(InitOptions{
+		SecretShares:    5,
+		SecretThreshold: 3,
+	})
+	require.Error(t, err)
+}
+
+func Test_Seal_UnsealReset(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	status, err := client.UnsealReset()
+	require.NoError(t, err)
+	require.False(t, status.Sealed)
+	require.Equal(t, 0, status.Progress)
+}
+
+// noTokener has no token to give, like a harness bringing up a new vault
+type noTokener struct{}
+
+func (noTokener) Token() (string, error) {
+	return "", errors.New("no token yet")
+}
+
+// sealServer pretends to be one member of a sealed vault cluster,
+// recording the paths and tokens of the requests it receives
+func sealServer(status int) (*httptest.Server, func() []string) {
+	var lock sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get(headerVaultToken))
+		lock.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"initialized": false, "sealed": true, "t": 3, "n": 5, "progress": 1}`))
+	}))
+	return server, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string{}, requests...)
+	}
+}
+
+func Test_Seal_singleServer(t *testing.T) {
+	broken, brokenRequests := sealServer(http.StatusInternalServerError)
+	defer broken.Close()
+	healthy, healthyRequests := sealServer(http.StatusOK)
+	defer healthy.Close()
+
+	client, err := New(ClientOptions{Servers: []string{broken.URL, healthy.URL}}, noTokener{})
+	require.NoError(t, err)
+
+	// a failure of the first server does not fall through to the others
+	_, err = client.Unseal("key1")
+	require.Error(t, err)
+	_, err = client.Init(InitOptions{SecretShares: 5, SecretThreshold: 3})
+	require.Error(t, err)
+	_, err = client.InitStatus()
+	require.Error(t, err)
+	_, err = client.UnsealReset()
+	require.Error(t, err)
+	require.Equal(t, []string{
+		"POST /v1/sys/unseal ",
+		"POST /v1/sys/init ",
+		"GET /v1/sys/init ",
+		"POST /v1/sys/unseal ",
+	}, brokenRequests())
+	require.Empty(t, healthyRequests())
+
+	// init and unseal are made without a token
+	status, err := client.UnsealServer(healthy.URL, "key1")
+	require.NoError(t, err)
+	require.Equal(t, 1, status.Progress)
+	initialized, err := client.InitStatusServer(healthy.URL)
+	require.NoError(t, err)
+	require.False(t, initialized)
+	require.Equal(t, []string{"POST /v1/sys/unseal ", "GET /v1/sys/init "}, healthyRequests())
+	require.Len(t, brokenRequests(), 4)
+
+	// sealing requires a token
+	require.Error(t, client.SealServer(healthy.URL))
+	require.Len(t, healthyRequests(), 2)
+}
+
+func Test_Seal_SealServer(t *testing.T) {
+	server, requests := sealServer(http.StatusNoContent)
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+	require.NoError(t, client.Seal())
+	require.Equal(t, []string{"PUT /v1/sys/seal abc123"}, requests())
+}
@@ -0,0 +1,236 @@
+package vaultapi
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// the names vault uses for each hash algorithm
+var transitHashAlgorithms = map[crypto.Hash]string{
+	crypto.SHA1:     "sha1",
+	crypto.SHA224:   "sha2-224",
+	crypto.SHA256:   "sha2-256",
+	crypto.SHA384:   "sha2-384",
+	crypto.SHA512:   "sha2-512",
+	crypto.SHA3_224: "sha3-224",
+	crypto.SHA3_256: "sha3-256",
+	crypto.SHA3_384: "sha3-384",
+	crypto.SHA3_512: "sha3-512",
+}
+
+// transitPrivateKey is a handle to the private half of an asymmetric
+// transit key, which never leaves vault
+type transitPrivateKey struct {
+	transit Transit
+	name    string
+	keyType string
+	version int
+	public  crypto.PublicKey
+}
+
+// NewTransitSigner creates a crypto.Signer which signs with the transit key
+// called name, which must be an ed25519, ecdsa, or rsa key. The latest
+// version of the key is used, so that signatures always correspond to the
+// public key of the signer, even if the key is later rotated.
+func NewTransitSigner(transit Transit, name string) (crypto.Signer, error) {
+	key, err := newTransitPrivateKey(transit, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(key.keyType, "ecdsa-") && !strings.HasPrefix(key.keyType, "rsa-") && key.keyType != TransitKeyED25519 {
+		return nil, errors.Errorf("transit key %q of type %q cannot be used for signing", name, key.keyType)
+	}
+
+	return key, nil
+}
+
+// NewTransitDecrypter creates a crypto.Decrypter which decrypts with the
+// transit key called name, which must be an rsa key. Vault only supports
+// decryption with RSA-OAEP using SHA-256.
+func NewTransitDecrypter(transit Transit, name string) (crypto.Decrypter, error) {
+	key, err := newTransitPrivateKey(transit, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(key.keyType, "rsa-") {
+		return nil, errors.Errorf("transit key %q of type %q cannot be used for decryption", name, key.keyType)
+	}
+
+	return key, nil
+}
+
+func newTransitPrivateKey(transit Transit, name string) (*transitPrivateKey, error) {
+	key, err := transit.ReadKey(name)
+	if err != nil {
+		return nil, err
+	}
+
+	version, exists := key.Versions[key.LatestVersion]
+	if !exists {
+		return nil, errors.Errorf("transit key %q is missing version %d", name, key.LatestVersion)
+	}
+
+	public, err := parseTransitPublicKey(key.Type, version.PublicKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse public key of transit key %q", name)
+	}
+
+	return &transitPrivateKey{
+		transit: transit,
+		name:    name,
+		keyType: key.Type,
+		version: key.LatestVersion,
+		public:  public,
+	}, nil
+}
+
+// parseTransitPublicKey decodes the public key of an asymmetric transit
+// key, which is base64 encoded for ed25519 keys, and PEM encoded otherwise
+func parseTransitPublicKey(keyType, encoded string) (crypto.PublicKey, error) {
+	if keyType == TransitKeyED25519 {
+		bs, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) != ed25519.PublicKeySize {
+			return nil, errors.Errorf("invalid ed25519 public key length %d", len(bs))
+		}
+		return ed25519.PublicKey(bs), nil
+	}
+
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// decodeTransitValue splits a value produced by transit, of the form
+// vault:v1:base64, into the key version and the decoded bytes
+func decodeTransitValue(value string) (int, []byte, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" || !strings.HasPrefix(parts[1], "v") {
+		return 0, nil, errors.New("malformed transit value")
+	}
+
+	version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil {
+		return 0, nil, errors.New("malformed transit value version")
+	}
+
+	bs, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "malformed transit value")
+	}
+
+	return version, bs, nil
+}
+
+func (k *transitPrivateKey) Public() crypto.PublicKey {
+	return k.public
+}
+
+// signOptions converts the crypto.SignerOpts into the equivalent
+// transit options for the type of key
+func (k *transitPrivateKey) signOptions(digest []byte, opts crypto.SignerOpts) ([]TransitOption, error) {
+	hash := opts.HashFunc()
+	transitOpts := []TransitOption{WithKeyVersion(k.version)}
+
+	// ed25519 signs the entire message, rather than a digest
+	if k.keyType == TransitKeyED25519 {
+		if hash != crypto.Hash(0) {
+			return nil, errors.New("ed25519 keys cannot sign a prehashed digest")
+		}
+		return transitOpts, nil
+	}
+
+	if hash != crypto.Hash(0) && len(digest) != hash.Size() {
+		return nil, errors.Errorf("digest length %d does not match hash size %d", len(digest), hash.Size())
+	}
+
+	algorithm, exists := transitHashAlgorithms[hash]
+	switch {
+	case exists:
+	case hash == crypto.Hash(0) && strings.HasPrefix(k.keyType, "rsa-"):
+		// vault signs the input as is with pkcs1v15
+		algorithm = "none"
+	default:
+		return nil, errors.Errorf("unsupported hash function %v", hash)
+	}
+	transitOpts = append(transitOpts, WithPrehashed(), WithHashAlgorithm(algorithm))
+
+	if !strings.HasPrefix(k.keyType, "rsa-") {
+		return transitOpts, nil
+	}
+
+	pss, isPSS := opts.(*rsa.PSSOptions)
+	if !isPSS {
+		return append(transitOpts, WithSignatureAlgorithm("pkcs1v15")), nil
+	}
+
+	if hash == crypto.Hash(0) {
+		return nil, errors.New("pss signatures require a hash function")
+	}
+
+	transitOpts = append(transitOpts, WithSignatureAlgorithm("pss"))
+	switch pss.SaltLength {
+	case rsa.PSSSaltLengthAuto:
+		transitOpts = append(transitOpts, withSaltLength("auto"))
+	case rsa.PSSSaltLengthEqualsHash:
+		transitOpts = append(transitOpts, withSaltLength("hash"))
+	default:
+		transitOpts = append(transitOpts, withSaltLength(strconv.Itoa(pss.SaltLength)))
+	}
+
+	return transitOpts, nil
+}
+
+func withSaltLength(length string) TransitOption {
+	return func(body map[string]interface{}) {
+		body["salt_length"] = length
+	}
+}
+
+// Sign signs digest with the transit key. The rand argument is ignored,
+// as the randomness is provided by vault.
+func (k *transitPrivateKey) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	transitOpts, err := k.signOptions(digest, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to sign with transit key %q", k.name)
+	}
+
+	signature, err := k.transit.Sign(k.name, digest, transitOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, bs, err := decodeTransitValue(signature)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode signature from transit key %q", k.name)
+	}
+
+	return bs, nil
+}
+
+// Decrypt decrypts msg with the transit key, which must have been encrypted
+// with RSA-OAEP using SHA-256 and no label. The rand argument is ignored.
+func (k *transitPrivateKey) Decrypt(_ io.Reader, msg []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	if oaep, ok := opts.(*rsa.OAEPOptions); !ok || oaep.Hash != crypto.SHA256 || len(oaep.Label) > 0 {
+		return nil, errors.Errorf("transit key %q only supports decryption with RSA-OAEP using SHA-256 and no label", k.name)
+	}
+
+	ciphertext := "vault:v" + strconv.Itoa(k.version) + ":" + base64.StdEncoding.EncodeToString(msg)
+	return k.transit.Decrypt(k.name, ciphertext)
+}
@@ -0,0 +1,195 @@
+package vaultapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransit signs and decrypts with a local key, the way vault would
+type fakeTransit struct {
+	Transit // not implemented
+
+	keyType string
+	private crypto.Signer
+	body    map[string]interface{}
+}
+
+func (f *fakeTransit) ReadKey(name string) (TransitKey, error) {
+	var public string
+	if f.keyType == TransitKeyED25519 {
+		public = base64.StdEncoding.EncodeToString(f.private.Public().(ed25519.PublicKey))
+	} else {
+		der, err := x509.MarshalPKIXPublicKey(f.private.Public())
+		if err != nil {
+			return TransitKey{}, err
+		}
+		public = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+
+	return TransitKey{
+		Name:          name,
+		Type:          f.keyType,
+		LatestVersion: 3,
+		Versions:      map[int]TransitKeyVersion{3: {PublicKey: public}},
+	}, nil
+}
+
+func (f *fakeTransit) Sign(key string, input []byte, opts ...TransitOption) (string, error) {
+	f.body = make(map[string]interface{})
+	for _, opt := range opts {
+		opt(f.body)
+	}
+
+	var signature []byte
+	var err error
+	switch private := f.private.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(private, input)
+	case *ecdsa.PrivateKey:
+		signature, err = private.Sign(rand.Reader, input, crypto.SHA256)
+	case *rsa.PrivateKey:
+		if f.body["signature_algorithm"] == "pss" {
+			signature, err = rsa.SignPSS(rand.Reader, private, crypto.SHA256, input, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, input)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return "vault:v3:" + base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func (f *fakeTransit) Decrypt(key, ciphertext string, _ ...TransitOption) ([]byte, error) {
+	version, msg, err := decodeTransitValue(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if version != 3 {
+		return nil, errors.Errorf("wrong version %d", version)
+	}
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, f.private.(*rsa.PrivateKey), msg, nil)
+}
+
+func Test_TransitSigner_ECDSA(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	fake := &fakeTransit{keyType: TransitKeyECDSAP256, private: private}
+
+	signer, err := NewTransitSigner(fake, "ec")
+	require.NoError(t, err)
+	require.Equal(t, private.Public(), signer.Public())
+
+	digest := sha256.Sum256([]byte("message"))
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	require.True(t, ecdsa.VerifyASN1(private.Public().(*ecdsa.PublicKey), digest[:], signature))
+
+	require.Equal(t, map[string]interface{}{
+		"key_version":    3,
+		"prehashed":      true,
+		"hash_algorithm": "sha2-256",
+	}, fake.body)
+
+	_, err = signer.Sign(rand.Reader, digest[:10], crypto.SHA256)
+	require.Error(t, err)
+}
+
+func Test_TransitSigner_RSA(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	fake := &fakeTransit{keyType: TransitKeyRSA2048, private: private}
+
+	signer, err := NewTransitSigner(fake, "rsa")
+	require.NoError(t, err)
+	public := signer.Public().(*rsa.PublicKey)
+
+	digest := sha256.Sum256([]byte("message"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	require.NoError(t, rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature))
+	require.Equal(t, "pkcs1v15", fake.body["signature_algorithm"])
+
+	pss := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+	signature, err = signer.Sign(rand.Reader, digest[:], pss)
+	require.NoError(t, err)
+	require.NoError(t, rsa.VerifyPSS(public, crypto.SHA256, digest[:], signature, pss))
+	require.Equal(t, "pss", fake.body["signature_algorithm"])
+	require.Equal(t, "hash", fake.body["salt_length"])
+
+	decrypter, err := NewTransitDecrypter(fake, "rsa")
+	require.NoError(t, err)
+
+	ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, public, []byte("secret"), nil)
+	require.NoError(t, err)
+
+	plaintext, err := decrypter.Decrypt(rand.Reader, ciphertext, &rsa.OAEPOptions{Hash: crypto.SHA256})
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), plaintext)
+
+	_, err = decrypter.Decrypt(rand.Reader, ciphertext, &rsa.PKCS1v15DecryptOptions{})
+	require.Error(t, err)
+}
+
+func Test_TransitSigner_ED25519(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	fake := &fakeTransit{keyType: TransitKeyED25519, private: private}
+
+	signer, err := NewTransitSigner(fake, "ed")
+	require.NoError(t, err)
+
+	signature, err := signer.Sign(rand.Reader, []byte("message"), crypto.Hash(0))
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(signer.Public().(ed25519.PublicKey), []byte("message"), signature))
+	require.Equal(t, map[string]interface{}{"key_version": 3}, fake.body)
+
+	_, err = signer.Sign(rand.Reader, []byte("message"), crypto.SHA256)
+	require.Error(t, err)
+
+	_, err = NewTransitDecrypter(fake, "ed")
+	require.Error(t, err)
+}
+
+func Test_decodeTransitValue(t *testing.T) {
+	version, bs, err := decodeTransitValue("vault:v12:aGVsbG8=")
+	require.NoError(t, err)
+	require.Equal(t, 12, version)
+	require.Equal(t, []byte("hello"), bs)
+
+	for _, value := range []string{"", "vault:v1", "foo:v1:aGVsbG8=", "vault:1:aGVsbG8=", "vault:vx:aGVsbG8=", "vault:v1:!!"} {
+		_, _, err := decodeTransitValue(value)
+		require.Error(t, err, "value: %s", value)
+	}
+}
+
+func Test_TransitSigner_DevVault(t *testing.T) {
+	transit := getTransit(t)
+
+	err := transit.CreateKey("test-signer", TransitKeyOptions{Type: TransitKeyECDSAP256})
+	require.NoError(t, err)
+	defer deleteTransitKey(t, transit, "test-signer")
+
+	signer, err := NewTransitSigner(transit, "test-signer")
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("message"))
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	require.True(t, ecdsa.VerifyASN1(signer.Public().(*ecdsa.PublicKey), digest[:], signature))
+}
@@ -0,0 +1,202 @@
+package vaultapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"gophers.dev/pkgs/ignore"
+)
+
+// A SnapshotInfo describes a raft snapshot which was
+// saved or restored.
+type SnapshotInfo struct {
+	// Size is the number of bytes in the snapshot.
+	Size int64
+
+	// SHA256 is the hex encoded checksum of the snapshot.
+	SHA256 string
+}
+
+// A SnapshotOption modifies how a raft snapshot is saved or restored.
+type SnapshotOption func(*snapshotOptions)
+
+type snapshotOptions struct {
+	progress func(int64)
+	checksum string
+}
+
+// WithProgress is a SnapshotOption which calls progress with the total
+// number of bytes transferred so far, as the snapshot is being streamed.
+func WithProgress(progress func(bytes int64)) SnapshotOption {
+	return func(so *snapshotOptions) {
+		so.progress = progress
+	}
+}
+
+// WithChecksum is a SnapshotOption for restoring a snapshot which aborts
+// the restore if the snapshot does not match the given hex encoded SHA-256
+// checksum, as returned in the SnapshotInfo when the snapshot was saved.
+func WithChecksum(sha256 string) SnapshotOption {
+	return func(so *snapshotOptions) {
+		so.checksum = sha256
+	}
+}
+
+func newSnapshotOptions(opts []SnapshotOption) snapshotOptions {
+	so := snapshotOptions{progress: func(int64) {}}
+	for _, opt := range opts {
+		opt(&so)
+	}
+	return so
+}
+
+// snapshotStream keeps track of the size and checksum of
+// a snapshot as it is being streamed
+type snapshotStream struct {
+	hash     hash.Hash
+	size     int64
+	progress func(int64)
+}
+
+func newSnapshotStream(progress func(int64)) *snapshotStream {
+	return &snapshotStream{
+		hash:     sha256.New(),
+		progress: progress,
+	}
+}
+
+func (s *snapshotStream) Write(p []byte) (int, error) {
+	_, _ = s.hash.Write(p)
+	s.size += int64(len(p))
+	s.progress(s.size)
+	return len(p), nil
+}
+
+func (s *snapshotStream) info() SnapshotInfo {
+	return SnapshotInfo{
+		Size:   s.size,
+		SHA256: hex.EncodeToString(s.hash.Sum(nil)),
+	}
+}
+
+// verifyingReader fails at the end of the snapshot if the checksum does not
+// match, which aborts the request before vault can restore the snapshot
+type verifyingReader struct {
+	reader   io.Reader
+	stream   *snapshotStream
+	checksum string
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.reader.Read(p)
+	if err == io.EOF && v.checksum != "" {
+		if sum := v.stream.info().SHA256; sum != v.checksum {
+			return n, errors.Errorf("snapshot checksum %s does not match expected %s", sum, v.checksum)
+		}
+	}
+	return n, err
+}
+
+// snapshots may be very large, so do not apply the
+// configured timeout to the entire request
+func (c *client) streamingClient() *http.Client {
+	streaming := *c.httpClient
+	streaming.Timeout = 0
+	return &streaming
+}
+
+// SnapshotSave streams a snapshot of the raft storage into w, without
+// buffering the snapshot in memory.
+func (c *client) SnapshotSave(w io.Writer, opts ...SnapshotOption) (SnapshotInfo, error) {
+	so := newSnapshotOptions(opts)
+
+	for _, address := range c.opts.Servers {
+		response, err := c.snapshotRequest(http.MethodGet, address+"/v1/sys/storage/raft/snapshot", nil)
+		if err != nil {
+			c.opts.Logger.Printf("GET request for snapshot failed: %v", err)
+			continue
+		}
+
+		// once the snapshot has started being written into w,
+		// the request cannot be retried with another server
+		stream := newSnapshotStream(so.progress)
+		_, err = io.Copy(io.MultiWriter(w, stream), response.Body)
+		ignore.Drain(response.Body)
+		if err != nil {
+			return SnapshotInfo{}, errors.Wrap(err, "failed to save snapshot")
+		}
+
+		return stream.info(), nil
+	}
+
+	return SnapshotInfo{}, errors.Errorf("all attempts for GET request failed to: %v", c.opts.Servers)
+}
+
+// SnapshotRestore streams the snapshot read from r into vault, replacing the
+// contents of the raft storage. If force is set, the snapshot is restored
+// even if it was taken of a different cluster, or with different keys.
+func (c *client) SnapshotRestore(r io.Reader, force bool, opts ...SnapshotOption) (SnapshotInfo, error) {
+	so := newSnapshotOptions(opts)
+
+	path := "/v1/sys/storage/raft/snapshot"
+	if force {
+		path = "/v1/sys/storage/raft/snapshot-force"
+	}
+
+	stream := newSnapshotStream(so.progress)
+	body := &verifyingReader{
+		reader:   io.TeeReader(r, stream),
+		stream:   stream,
+		checksum: so.checksum,
+	}
+
+	for _, address := range c.opts.Servers {
+		response, err := c.snapshotRequest(http.MethodPost, address+path, body)
+		if err != nil {
+			// the request can only be retried with another
+			// server if none of the snapshot was consumed
+			if stream.size > 0 {
+				return SnapshotInfo{}, errors.Wrap(err, "failed to restore snapshot")
+			}
+			c.opts.Logger.Printf("POST request for snapshot failed: %v", err)
+			continue
+		}
+		ignore.Drain(response.Body)
+
+		return stream.info(), nil
+	}
+
+	return SnapshotInfo{}, errors.Errorf("all attempts for POST request failed to: %v", c.opts.Servers)
+}
+
+func (c *client) snapshotRequest(method, url string, body io.Reader) (*http.Response, error) {
+	request, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build %s request to %q", method, url)
+	}
+
+	token, err := c.token()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token for request")
+	}
+
+	request.Header.Set(headerVaultToken, token)
+	c.setHeaders(request)
+
+	response, err := c.streamingClient().Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to execute %s request to %q", method, url)
+	}
+
+	if response.StatusCode >= 400 {
+		ignore.Drain(response.Body)
+		return nil, errors.Errorf("bad status code: %d, url: %s", response.StatusCode, url)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,104 @@
+package vaultapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Snapshot_DevVault(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	// the dev vault uses in-memory storage rather than raft
+	var buf bytes.Buffer
+	_, err := client.SnapshotSave(&buf)
+	require.Error(t, err)
+	require.Equal(t, 0, buf.Len())
+}
+
+// a snapshotRequest is a request received by snapshotServer
+type snapshotRequest struct {
+	method string
+	token  string
+	body   []byte
+}
+
+// snapshotServer pretends to be vault, serving snapshot and recording
+// each request it receives, along with any snapshot that is restored
+func snapshotServer(snapshot []byte) (*httptest.Server, func() []snapshotRequest) {
+	var lock sync.Mutex
+	var requests []snapshotRequest
+	record := func(request snapshotRequest) {
+		lock.Lock()
+		requests = append(requests, request)
+		lock.Unlock()
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := snapshotRequest{method: r.Method, token: r.Header.Get(headerVaultToken)}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/sys/storage/raft/snapshot":
+			record(request)
+			_, _ = w.Write(snapshot)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/sys/storage/raft/snapshot-force":
+			bs, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return // aborted by the client
+			}
+			request.body = bs
+			record(request)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server, func() []snapshotRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]snapshotRequest{}, requests...)
+	}
+}
+
+func Test_Snapshot_SaveRestore(t *testing.T) {
+	snapshot := bytes.Repeat([]byte("snapshot data "), 10000)
+	sum := sha256.Sum256(snapshot)
+	server, requests := snapshotServer(snapshot)
+	defer server.Close()
+
+	client, err := New(ClientOptions{
+		Servers: []string{"http://localhost:1", server.URL},
+	}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	var saved bytes.Buffer
+	var progress int64
+	info, err := client.SnapshotSave(&saved, WithProgress(func(bytes int64) {
+		progress = bytes
+	}))
+	require.NoError(t, err)
+	require.Equal(t, snapshot, saved.Bytes())
+	require.Equal(t, int64(len(snapshot)), info.Size)
+	require.Equal(t, int64(len(snapshot)), progress)
+	require.Equal(t, hex.EncodeToString(sum[:]), info.SHA256)
+
+	// the checksum does not match, so the restore is aborted
+	_, err = client.SnapshotRestore(bytes.NewReader(saved.Bytes()), true, WithChecksum("0000"))
+	require.Error(t, err)
+	require.Len(t, requests(), 1)
+
+	restoredInfo, err := client.SnapshotRestore(bytes.NewReader(saved.Bytes()), true, WithChecksum(info.SHA256))
+	require.NoError(t, err)
+	require.Equal(t, info, restoredInfo)
+	require.Equal(t, []snapshotRequest{
+		{method: http.MethodGet, token: "abc123"},
+		{method: http.MethodPost, token: "abc123", body: snapshot},
+	}, requests())
+}
@@ -0,0 +1,248 @@
+package vaultapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PolicyAction is the action taken on a policy when syncing policies.
+type PolicyAction string
+
+const (
+	PolicyCreate PolicyAction = "create"
+	PolicyUpdate PolicyAction = "update"
+	PolicyDelete PolicyAction = "delete"
+)
+
+// the policies vault will not allow to be deleted
+var protectedPolicies = []string{"root", "default"}
+
+// PolicySyncOptions are used to configure how SyncPolicies
+// reconciles policies in vault.
+type PolicySyncOptions struct {
+	// DryRun causes SyncPolicies to only compute the plan, without
+	// making any changes to vault.
+	DryRun bool
+
+	// Protected is a list of policies which must never be deleted,
+	// in addition to the root and default policies.
+	Protected []string
+}
+
+// A PolicyChange is one change to be made to vault by SyncPolicies.
+type PolicyChange struct {
+	Name   string
+	Action PolicyAction
+
+	// Current and Desired are the normalized forms of the policy in
+	// vault and of the desired policy, which are empty when creating
+	// or deleting the policy respectively.
+	Current string
+	Desired string
+
+	// Applied indicates whether the change was made.
+	Applied bool
+}
+
+// A PolicyPlan describes the changes needed to make the policies
+// in vault match the desired policies.
+type PolicyPlan struct {
+	Changes   []PolicyChange
+	Unchanged []string
+
+	// Protected are the policies which are not desired, but
+	// were not deleted because they are protected.
+	Protected []string
+}
+
+// String renders the plan in a form suitable for review.
+func (p PolicyPlan) String() string {
+	if len(p.Changes) == 0 {
+		return "no changes"
+	}
+
+	symbols := map[PolicyAction]string{
+		PolicyCreate: "+",
+		PolicyUpdate: "~",
+		PolicyDelete: "-",
+	}
+
+	var lines []string
+	for _, change := range p.Changes {
+		lines = append(lines, fmt.Sprintf("%s %s %s", symbols[change.Action], change.Action, change.Name))
+	}
+	lines = append(lines, fmt.Sprintf("%d to change, %d unchanged, %d protected", len(p.Changes), len(p.Unchanged), len(p.Protected)))
+	return strings.Join(lines, "\n")
+}
+
+// normalizePolicy renders the policy in a canonical form, so that
+// differences of whitespace, comments, formatting, and the order of
+// capabilities and required parameters are ignored
+func normalizePolicy(document string) string {
+	if policy, err := ParsePolicy(document); err == nil {
+		for i, rule := range policy.Paths {
+			policy.Paths[i].Capabilities = sortedCopy(rule.Capabilities)
+			policy.Paths[i].RequiredParameters = sortedCopy(rule.RequiredParameters)
+		}
+		return policy.HCL()
+	}
+
+	// not a policy this package understands, fall back to
+	// ignoring only differences of whitespace
+	var lines []string
+	for _, line := range strings.Split(document, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, strings.Join(fields, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// planPolicies computes the changes needed to turn current into desired
+func planPolicies(current, desired map[string]string, protected []string) PolicyPlan {
+	var plan PolicyPlan
+
+	for _, name := range sortedPolicyNames(desired) {
+		want := normalizePolicy(desired[name])
+		existing, exists := current[name]
+		switch {
+		case !exists:
+			plan.Changes = append(plan.Changes, PolicyChange{Name: name, Action: PolicyCreate, Desired: want})
+		case normalizePolicy(existing) != want:
+			plan.Changes = append(plan.Changes, PolicyChange{Name: name, Action: PolicyUpdate, Current: normalizePolicy(existing), Desired: want})
+		default:
+			plan.Unchanged = append(plan.Unchanged, name)
+		}
+	}
+
+	for _, name := range sortedPolicyNames(current) {
+		if _, exists := desired[name]; exists {
+			continue
+		}
+		if containsString(protectedPolicies, name) || containsString(protected, name) {
+			plan.Protected = append(plan.Protected, name)
+			continue
+		}
+		plan.Changes = append(plan.Changes, PolicyChange{Name: name, Action: PolicyDelete, Current: normalizePolicy(current[name])})
+	}
+
+	return plan
+}
+
+func sortedCopy(strs []string) []string {
+	if strs == nil {
+		return nil
+	}
+	sorted := append([]string{}, strs...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func sortedPolicyNames(policies map[string]string) []string {
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SyncPolicies reconciles the policies in vault with desired, which maps
+// the name of each policy to its document. Policies missing from vault are
+// created, policies that differ are updated, and policies not in desired
+// are deleted unless they are protected. Every desired policy which this
+// package understands is validated before any change is made. Policies it
+// does not understand, e.g. those using enterprise only features, are left
+// for vault to validate, and are compared ignoring only whitespace.
+func (c *client) SyncPolicies(desired map[string]string, opts PolicySyncOptions) (PolicyPlan, error) {
+	for _, name := range sortedPolicyNames(desired) {
+		if name == "root" {
+			return PolicyPlan{}, errors.New("the root policy cannot be modified")
+		}
+
+		policy, err := ParsePolicy(desired[name])
+		if err != nil {
+			c.opts.Logger.Printf("sync policies: policy %q will be validated by vault: %v", name, err)
+			continue
+		}
+
+		if err := policy.Validate(); err != nil {
+			return PolicyPlan{}, errors.Wrapf(err, "invalid desired policy %q", name)
+		}
+	}
+
+	names, err := c.ListPolicies()
+	if err != nil {
+		return PolicyPlan{}, errors.Wrap(err, "failed to sync policies")
+	}
+
+	current := make(map[string]string, len(names))
+	for _, name := range names {
+		// the root policy cannot be read, and is always protected
+		if name == "root" {
+			continue
+		}
+		content, err := c.GetPolicy(name)
+		if err != nil {
+			return PolicyPlan{}, errors.Wrap(err, "failed to sync policies")
+		}
+		current[name] = content
+	}
+
+	plan := planPolicies(current, desired, opts.Protected)
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	for i, change := range plan.Changes {
+		switch change.Action {
+		case PolicyCreate, PolicyUpdate:
+			err = c.SetPolicy(change.Name, desired[change.Name])
+		case PolicyDelete:
+			err = c.DeletePolicy(change.Name)
+		}
+		if err != nil {
+			return plan, errors.Wrapf(err, "failed to %s policy %q", change.Action, change.Name)
+		}
+		plan.Changes[i].Applied = true
+		c.opts.Logger.Printf("sync policies: %s policy %q", change.Action, change.Name)
+	}
+
+	return plan, nil
+}
+
+// ReadPolicyDir reads the policy documents in dir, which are the files
+// ending in .hcl or .json, keyed by file name without the extension.
+// The result is suitable for use with SyncPolicies.
+func ReadPolicyDir(dir string) (map[string]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read policy directory %q", dir)
+	}
+
+	policies := make(map[string]string)
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || (ext != ".hcl" && ext != ".json") {
+			continue
+		}
+
+		name := strings.TrimSuffix(file.Name(), ext)
+		if _, exists := policies[name]; exists {
+			return nil, errors.Errorf("policy %q is defined by more than one file in %q", name, dir)
+		}
+
+		bs, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read policy file %q", file.Name())
+		}
+		policies[name] = string(bs)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,180 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_normalizePolicy(t *testing.T) {
+	a := `path "secret/*" { capabilities = ["read", "list"] }`
+	b := `
+# a comment
+path "secret/*" {
+	capabilities = [
+		"read",
+		"list",
+	]
+}`
+	require.Equal(t, normalizePolicy(a), normalizePolicy(b))
+	require.NotEqual(t, normalizePolicy(a), normalizePolicy(`path "secret/*" { capabilities = ["read"] }`))
+
+	// capabilities are a set, so their order does not matter
+	require.Equal(t, normalizePolicy(a), normalizePolicy(`path "secret/*" { capabilities = ["list", "read"] }`))
+
+	// documents which cannot be parsed only ignore whitespace
+	require.Equal(t, "control_group = {\n}", normalizePolicy("  control_group   =  {\n\n }"))
+}
+
+func Test_planPolicies(t *testing.T) {
+	plan := planPolicies(
+		map[string]string{
+			"default": `path "auth/token/lookup-self" { capabilities = ["read"] }`,
+			"same":    `path "secret/*" { capabilities = ["read"] }`,
+			"changed": `path "secret/*" { capabilities = ["read"] }`,
+			"stale":   `path "secret/*" { capabilities = ["read"] }`,
+			"keep":    `path "secret/*" { capabilities = ["read"] }`,
+			"reorder": `path "secret/*" { capabilities = ["read", "update"] }`,
+		},
+		map[string]string{
+			"same":    "path \"secret/*\" {\n  capabilities = [\"read\"]\n}\n",
+			"changed": `path "secret/*" { capabilities = ["read", "list"] }`,
+			"reorder": `path "secret/*" { capabilities = ["update", "read"] }`,
+			"new":     `path "secret/*" { capabilities = ["list"] }`,
+		},
+		[]string{"keep"},
+	)
+
+	require.Equal(t, []string{"reorder", "same"}, plan.Unchanged)
+	require.Equal(t, []string{"default", "keep"}, plan.Protected)
+	require.Equal(t, 3, len(plan.Changes))
+	require.Equal(t, PolicyChange{Name: "changed", Action: PolicyUpdate,
+		Current: "path \"secret/*\" {\n  capabilities = [\"read\"]\n}\n",
+		Desired: "path \"secret/*\" {\n  capabilities = [\"list\", \"read\"]\n}\n",
+	}, plan.Changes[0])
+	require.Equal(t, "new", plan.Changes[1].Name)
+	require.Equal(t, PolicyCreate, plan.Changes[1].Action)
+	require.Equal(t, "stale", plan.Changes[2].Name)
+	require.Equal(t, PolicyDelete, plan.Changes[2].Action)
+
+	require.Equal(t, "~ update changed\n+ create new\n- delete stale\n3 to change, 2 unchanged, 2 protected", plan.String())
+}
+
+func Test_Client_SyncPolicies_unparsed(t *testing.T) {
+	enterprise := `path "secret/*" {
+  capabilities = ["read"]
+  control_group = {
+    factor "approvers" {
+      identity { group_names = ["approvers"] }
+    }
+  }
+}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/v1/sys/policy") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string][]string{"policies": {"root", "default", "enterprise"}})
+		case "/default":
+			_ = json.NewEncoder(w).Encode(map[string]string{"rules": `path "auth/token/lookup-self" { capabilities = ["read"] }`})
+		case "/enterprise":
+			_ = json.NewEncoder(w).Encode(map[string]string{"rules": enterprise})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	// a policy this package cannot parse is synced from the same text
+	_, err = ParsePolicy(enterprise)
+	require.Error(t, err)
+	plan, err := client.SyncPolicies(map[string]string{
+		"enterprise": "\n" + strings.Replace(enterprise, "  ", "\t", -1),
+	}, PolicySyncOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Empty(t, plan.Changes)
+	require.Equal(t, []string{"enterprise"}, plan.Unchanged)
+
+	// policies which can be parsed are still validated
+	_, err = client.SyncPolicies(map[string]string{
+		"enterprise": `path "secret/*" { capabilities = ["bogus"] }`,
+	}, PolicySyncOptions{DryRun: true})
+	require.Error(t, err)
+}
+
+func Test_ReadPolicyDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policies")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		require.NoError(t, err)
+	}
+	write("foo.hcl", pol1)
+	write("bar.json", pol2JSON)
+	write("README.md", "# policies")
+
+	policies, err := ReadPolicyDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"foo": pol1, "bar": pol2JSON}, policies)
+
+	write("foo.json", pol2JSON)
+	_, err = ReadPolicyDir(dir)
+	require.Error(t, err)
+}
+
+func Test_Client_SyncPolicies(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	desired := map[string]string{
+		"my_policy1": pol3,
+		"synced":     pol1,
+	}
+
+	// the dev vault is shared with other tests, so protect
+	// every policy which is not part of this test
+	existing, err := client.ListPolicies()
+	require.NoError(t, err)
+	var protected []string
+	for _, name := range existing {
+		if _, exists := desired[name]; !exists {
+			protected = append(protected, name)
+		}
+	}
+	opts := PolicySyncOptions{Protected: protected}
+
+	plan, err := client.SyncPolicies(desired, PolicySyncOptions{DryRun: true, Protected: protected})
+	require.NoError(t, err)
+	t.Log("plan:", plan)
+	require.Contains(t, plan.Protected, "default")
+	for _, change := range plan.Changes {
+		require.False(t, change.Applied)
+	}
+
+	plan, err = client.SyncPolicies(desired, opts)
+	require.NoError(t, err)
+	require.Contains(t, plan.Changes, PolicyChange{Name: "synced", Action: PolicyCreate, Desired: normalizePolicy(pol1), Applied: true})
+	for _, change := range plan.Changes {
+		require.NotEqual(t, PolicyDelete, change.Action)
+	}
+
+	plan, err = client.SyncPolicies(desired, opts)
+	require.NoError(t, err)
+	require.Empty(t, plan.Changes)
+	require.Equal(t, []string{"my_policy1", "synced"}, plan.Unchanged)
+
+	_, err = client.SyncPolicies(map[string]string{"root": pol1}, PolicySyncOptions{})
+	require.Error(t, err)
+
+	err = client.DeletePolicy("synced")
+	require.NoError(t, err)
+}
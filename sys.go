@@ -2,7 +2,11 @@ package vaultapi
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -16,22 +20,97 @@ type Sys interface {
 	AccessorCapabilities(path, accessor string) ([]string, error)
 	TokenCapabilities(path, token string) ([]string, error)
 	SelfCapabilities(path string) ([]string, error)
+	AccessorCapabilitiesMulti(paths []string, accessor string) (map[string][]string, error)
+	TokenCapabilitiesMulti(paths []string, token string) (map[string][]string, error)
+	SelfCapabilitiesMulti(paths []string) (map[string][]string, error)
+	RequireCapabilities(required map[string][]string) error
 
 	// Leases
 	LookupLease(id string) (Lease, error)
+	RenewLease(id string, increment time.Duration) (RenewedLease, error)
+	RevokeLease(id string) error
+	RevokeLeasePrefix(prefix string) error
+	RevokeLeaseForce(prefix string) error
+	ListLeases(prefix string) ([]string, error)
+
+	// Audit Devices
+	ListAudit() (AuditDevices, error)
+	EnableAudit(path, auditType string, options map[string]string) error
+	DisableAudit(path string) error
+	AuditHash(path, input string) (string, error)
+
+	// Response Wrapping
+	Wrap(data map[string]interface{}, ttl time.Duration) (WrapInfo, error)
+	Unwrap(token string) (*Secret, error)
+	RewrapToken(token string) (WrapInfo, error)
+	LookupWrapping(token string) (WrappingLookup, error)
 
 	// Policies
 	ListPolicies() ([]string, error)
 	GetPolicy(name string) (string, error)
 	SetPolicy(name, content string) error
 	DeletePolicy(name string) error
+	GetPolicyParsed(name string) (Policy, error)
+	SetPolicyTyped(name string, policy Policy) error
+	LintPolicies() ([]PolicyFinding, error)
+	SyncPolicies(desired map[string]string, opts PolicySyncOptions) (PolicyPlan, error)
 
 	// Vault Status
 	Health() (Health, error)
+	ServerHealth(address string, opts HealthOptions) (Health, error)
+	ServersHealth(opts HealthOptions) (map[string]Health, error)
+	ClusterStatus() (ClusterStatus, error)
 	Leader() (Leader, error)
 	StepDown() error
 	SealStatus() (SealStatus, error)
+
+	// Seal
+	InitStatus() (bool, error)
+	InitStatusServer(address string) (bool, error)
+	Init(opts InitOptions) (InitResponse, error)
+	InitServer(address string, opts InitOptions) (InitResponse, error)
+	Seal() error
+	SealServer(address string) error
+	Unseal(key string) (SealStatus, error)
+	UnsealServer(address, key string) (SealStatus, error)
+	UnsealReset() (SealStatus, error)
+	UnsealResetServer(address string) (SealStatus, error)
+
+	// Rekey
+	RekeyStatus() (RekeyStatus, error)
+	RekeyInit(opts RekeyOptions) (RekeyStatus, error)
+	RekeyUpdate(key, nonce string) (RekeyStatus, error)
+	RekeyCancel() error
+
+	// Generate Root
+	GenerateRootStatus() (GenerateRootStatus, error)
+	GenerateRootInit(otp string) (GenerateRootStatus, error)
+	GenerateRootUpdate(key, nonce string) (GenerateRootStatus, error)
+	GenerateRootCancel() error
+
+	// Raft Integrated Storage
+	RaftConfiguration() (RaftConfiguration, error)
+	RaftRemovePeer(id string) error
+	RaftJoin(opts RaftJoinOptions) (bool, error)
+	RaftAutopilotState() (AutopilotState, error)
+	RaftAutopilotConfig() (AutopilotConfig, error)
+	SetRaftAutopilotConfig(config AutopilotConfig) error
+	SnapshotSave(w io.Writer, opts ...SnapshotOption) (SnapshotInfo, error)
+	SnapshotRestore(r io.Reader, force bool, opts ...SnapshotOption) (SnapshotInfo, error)
+
+	// Secrets Engines
 	ListMounts() (Mounts, error)
+	EnableMount(path, mountType string, config MountConfig) error
+	DisableMount(path string) error
+	TuneMount(path string, config MountConfig) error
+	ReadMountTune(path string) (MountConfig, error)
+	Remount(from, to string) error
+
+	// Auth Methods
+	ListAuthMounts() (AuthMounts, error)
+	EnableAuth(path, authType string, config MountConfig) error
+	DisableAuth(path string) error
+	TuneAuth(path string, config MountConfig) error
 }
 
 type capabilities struct {
@@ -91,53 +170,106 @@ func (c *client) SelfCapabilities(path string) ([]string, error) {
 // indicates a TTL. Once the lease expires, that token is no longer
 // valid and cannot be used to authenticate with vault.
 type Lease struct {
-	ID              string `json:"id"`
-	IssueTime       string `json:"issue_time"`
-	ExpireTime      string `json:"expire_time"`
-	LastRenewalTime string `json:"last_renewal_time"`
-	Renewable       bool   `json:"renewable"`
-	TTL             int    `json:"ttl"`
+	ID              string    `json:"id"`
+	IssueTime       time.Time `json:"issue_time"`
+	ExpireTime      time.Time `json:"expire_time"`
+	LastRenewalTime time.Time `json:"last_renewal"`
+	Renewable       bool      `json:"renewable"`
+	TTL             int       `json:"ttl"`
+}
+
+type leaseWrapper struct {
+	Data Lease `json:"data"`
+}
+
+type leaseID struct {
+	ID string `json:"lease_id"`
 }
 
 func (c *client) LookupLease(id string) (Lease, error) {
-	bs, err := json.Marshal(struct {
-		ID string `json:"lease_id"`
-	}{ID: id})
+	bs, err := json.Marshal(leaseID{ID: id})
 	if err != nil {
 		return Lease{}, err
 	}
-	var lease Lease
-	if err := c.put("/v1/sys/leases/lookup", string(bs)); err != nil {
+	var wrapper leaseWrapper
+	if err := c.post("/v1/sys/leases/lookup", string(bs), &wrapper); err != nil {
 		return Lease{}, errors.Wrapf(err, "failed to lookup lease for %q", id)
 	}
+	return wrapper.Data, nil
+}
+
+// A RenewedLease represents information returned from
+// vault after making a request to renew a lease.
+type RenewedLease struct {
+	ID            string `json:"lease_id"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+func (c *client) RenewLease(id string, increment time.Duration) (RenewedLease, error) {
+	bs, err := json.Marshal(struct {
+		ID        string `json:"lease_id"`
+		Increment int    `json:"increment,omitempty"`
+	}{ID: id, Increment: int(increment.Seconds())})
+	if err != nil {
+		return RenewedLease{}, err
+	}
+	var lease RenewedLease
+	if err := c.post("/v1/sys/leases/renew", string(bs), &lease); err != nil {
+		return RenewedLease{}, errors.Wrapf(err, "failed to renew lease for %q", id)
+	}
 	return lease, nil
 }
 
-// A Health is returned upon requesting health status from vault
-// and contains some metadata about the vault configuartion.
-type Health struct {
-	Initialized   bool   `json:"initialized"`
-	Sealed        bool   `json:"sealed"`
-	Standby       bool   `json:"standby"`
-	ServerTimeUTC int    `json:"server_time_utc"`
-	Version       string `json:"version"`
-	ClusterName   string `json:"cluster_name"`
-	ClusterID     string `json:"cluster_id"`
+func (c *client) RevokeLease(id string) error {
+	bs, err := json.Marshal(leaseID{ID: id})
+	if err != nil {
+		return err
+	}
+	if err := c.put("/v1/sys/leases/revoke", string(bs)); err != nil {
+		return errors.Wrapf(err, "failed to revoke lease for %q", id)
+	}
+	return nil
+}
+
+func (c *client) RevokeLeasePrefix(prefix string) error {
+	if err := c.put("/v1/sys/leases/revoke-prefix/"+strings.TrimPrefix(prefix, "/"), ""); err != nil {
+		return errors.Wrapf(err, "failed to revoke leases with prefix %q", prefix)
+	}
+	return nil
 }
 
-func (c *client) Health() (Health, error) {
-	var health Health
-	if err := c.get("/v1/sys/health", &health); err != nil {
-		return Health{}, errors.Wrap(err, "failed to read health")
+// RevokeLeaseForce revokes all leases with prefix, ignoring any errors
+// encountered by the backend while doing so. This is meant for recovery
+// situations where the backend is no longer able to revoke its secrets,
+// and requires a token with sudo capability.
+func (c *client) RevokeLeaseForce(prefix string) error {
+	if err := c.put("/v1/sys/leases/revoke-force/"+strings.TrimPrefix(prefix, "/"), ""); err != nil {
+		return errors.Wrapf(err, "failed to force revoke leases with prefix %q", prefix)
 	}
-	return health, nil
+	return nil
+}
+
+func (c *client) ListLeases(prefix string) ([]string, error) {
+	var wrapper keysData
+	requestPath := "/v1/sys/leases/lookup/" + strings.TrimPrefix(prefix, "/")
+	if err := c.list(requestPath, &wrapper); err == ErrPathNotFound {
+		// vault responds with not found when there are no leases under prefix
+		return []string{}, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to list leases with prefix %q", prefix)
+	}
+	keys := wrapper.Data["keys"]
+	sort.Strings(keys)
+	return keys, nil
 }
 
 // A Leader is returned upon requesting the leader from vault.
 type Leader struct {
-	HAEnabled     bool   `json:"ha_enabled"`
-	IsSelf        bool   `json:"is_self"`
-	LeaderAddress string `json:"leader_address"`
+	HAEnabled            bool   `json:"ha_enabled"`
+	IsSelf               bool   `json:"is_self"`
+	LeaderAddress        string `json:"leader_address"`
+	LeaderClusterAddress string `json:"leader_cluster_address"`
 }
 
 func (c *client) Leader() (Leader, error) {
@@ -155,26 +287,194 @@ func (c *client) StepDown() error {
 	return nil
 }
 
+// A Mount describes an auth method or secrets engine that is
+// mounted at some path in vault.
+type Mount struct {
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Accessor    string            `json:"accessor"`
+	Local       bool              `json:"local"`
+	SealWrap    bool              `json:"seal_wrap"`
+	Options     map[string]string `json:"options"`
+	Config      MountConfig       `json:"config"`
+}
+
+// A MountConfig contains the tunable configuration of a mounted auth
+// method or secrets engine. When used to enable or tune a mount, nil fields
+// and empty strings are not sent to vault, and therefore retain whatever
+// value vault already has for them. A TTL of zero resets it to the system
+// default, an empty non-nil list clears it, and TTLs are sent to vault in
+// whole seconds, rounding up.
+//
+// More information can be found here:
+// https://www.vaultproject.io/api/system/auth.html
+type MountConfig struct {
+	Description               string
+	DefaultLeaseTTL           *time.Duration
+	MaxLeaseTTL               *time.Duration
+	ForceNoCache              *bool
+	AuditNonHMACRequestKeys   []string
+	AuditNonHMACResponseKeys  []string
+	ListingVisibility         *string
+	PassthroughRequestHeaders []string
+	AllowedResponseHeaders    []string
+	TokenType                 string
+
+	// Options are passed through to the mounted backend, for example
+	// the "version" option of the kv secrets engine.
+	Options map[string]string
+}
+
+// vault accepts TTLs as duration strings, but reports them as seconds
+type mountConfigRequest struct {
+	Description               string            `json:"description,omitempty"`
+	DefaultLeaseTTL           *string           `json:"default_lease_ttl,omitempty"`
+	MaxLeaseTTL               *string           `json:"max_lease_ttl,omitempty"`
+	ForceNoCache              *bool             `json:"force_no_cache,omitempty"`
+	AuditNonHMACRequestKeys   *[]string         `json:"audit_non_hmac_request_keys,omitempty"`
+	AuditNonHMACResponseKeys  *[]string         `json:"audit_non_hmac_response_keys,omitempty"`
+	ListingVisibility         *string           `json:"listing_visibility,omitempty"`
+	PassthroughRequestHeaders *[]string         `json:"passthrough_request_headers,omitempty"`
+	AllowedResponseHeaders    *[]string         `json:"allowed_response_headers,omitempty"`
+	TokenType                 string            `json:"token_type,omitempty"`
+	Options                   map[string]string `json:"options,omitempty"`
+}
+
+type mountConfigResponse struct {
+	Description               string            `json:"description"`
+	DefaultLeaseTTL           int               `json:"default_lease_ttl"`
+	MaxLeaseTTL               int               `json:"max_lease_ttl"`
+	ForceNoCache              bool              `json:"force_no_cache"`
+	AuditNonHMACRequestKeys   []string          `json:"audit_non_hmac_request_keys"`
+	AuditNonHMACResponseKeys  []string          `json:"audit_non_hmac_response_keys"`
+	ListingVisibility         string            `json:"listing_visibility"`
+	PassthroughRequestHeaders []string          `json:"passthrough_request_headers"`
+	AllowedResponseHeaders    []string          `json:"allowed_response_headers"`
+	TokenType                 string            `json:"token_type"`
+	Options                   map[string]string `json:"options"`
+}
+
+// ttlString renders ttl in whole seconds, rounding up, as vault does not
+// accept fractions of a second. A ttl that is not positive is not sent.
+func ttlString(ttl time.Duration) string {
+	if ttl <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%ds", (ttl+time.Second-1)/time.Second)
+}
+
+// mountTTL renders the ttl of a mount, where zero resets
+// the ttl to the system default
+func mountTTL(name string, ttl *time.Duration) (*string, error) {
+	if ttl == nil {
+		return nil, nil
+	}
+
+	var s string
+	switch {
+	case *ttl < 0:
+		return nil, errors.Errorf("%s must not be negative, got %v", name, *ttl)
+	case *ttl == 0:
+		s = "system"
+	default:
+		s = ttlString(*ttl)
+	}
+	return &s, nil
+}
+
+// presentList distinguishes a nil list, which is not sent to
+// vault, from an empty list, which clears the list in vault
+func presentList(list []string) *[]string {
+	if list == nil {
+		return nil
+	}
+	return &list
+}
+
+// MarshalJSON encodes mc in the form expected by vault.
+func (mc MountConfig) MarshalJSON() ([]byte, error) {
+	defaultLeaseTTL, err := mountTTL("default lease ttl", mc.DefaultLeaseTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLeaseTTL, err := mountTTL("max lease ttl", mc.MaxLeaseTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mountConfigRequest{
+		Description:               mc.Description,
+		DefaultLeaseTTL:           defaultLeaseTTL,
+		MaxLeaseTTL:               maxLeaseTTL,
+		ForceNoCache:              mc.ForceNoCache,
+		AuditNonHMACRequestKeys:   presentList(mc.AuditNonHMACRequestKeys),
+		AuditNonHMACResponseKeys:  presentList(mc.AuditNonHMACResponseKeys),
+		ListingVisibility:         mc.ListingVisibility,
+		PassthroughRequestHeaders: presentList(mc.PassthroughRequestHeaders),
+		AllowedResponseHeaders:    presentList(mc.AllowedResponseHeaders),
+		TokenType:                 mc.TokenType,
+		Options:                   mc.Options,
+	})
+}
+
+// UnmarshalJSON decodes mc from the form returned by vault.
+func (mc *MountConfig) UnmarshalJSON(bs []byte) error {
+	var response mountConfigResponse
+	if err := json.Unmarshal(bs, &response); err != nil {
+		return err
+	}
+
+	defaultLeaseTTL := time.Duration(response.DefaultLeaseTTL) * time.Second
+	maxLeaseTTL := time.Duration(response.MaxLeaseTTL) * time.Second
+
+	*mc = MountConfig{
+		Description:               response.Description,
+		DefaultLeaseTTL:           &defaultLeaseTTL,
+		MaxLeaseTTL:               &maxLeaseTTL,
+		ForceNoCache:              &response.ForceNoCache,
+		AuditNonHMACRequestKeys:   response.AuditNonHMACRequestKeys,
+		AuditNonHMACResponseKeys:  response.AuditNonHMACResponseKeys,
+		ListingVisibility:         &response.ListingVisibility,
+		PassthroughRequestHeaders: response.PassthroughRequestHeaders,
+		AllowedResponseHeaders:    response.AllowedResponseHeaders,
+		TokenType:                 response.TokenType,
+		Options:                   response.Options,
+	}
+	return nil
+}
+
+type enableMount struct {
+	Type        string            `json:"type"`
+	Description string            `json:"description,omitempty"`
+	Options     map[string]string `json:"options,omitempty"`
+	Config      MountConfig       `json:"config"`
+}
+
+// newEnableMount creates the request to enable a mount, where vault expects
+// the description and options beside the config rather than within it
+func newEnableMount(mountType string, config MountConfig) enableMount {
+	request := enableMount{
+		Type:        mountType,
+		Description: config.Description,
+		Options:     config.Options,
+		Config:      config,
+	}
+	request.Config.Description = ""
+	request.Config.Options = nil
+	return request
+}
+
 type mountsWrapper struct {
 	Data Mounts `json:"data"`
 }
 
-// Mounts contains information about the mounts currently configured
-// with vault. Generally, users of the vaultapi client library are
-// likely only concerned with the default backends that come with vault,
-// and will not need to be concerned with this API.
+// Mounts contains information about the secrets engines currently
+// mounted in vault, keyed by the path at which each is mounted.
 //
 // More information can be found here:
-// https://www.vaultproject.io/docs/internals/architecture.html
-type Mounts map[string]struct {
-	Type        string `json:"type"`
-	Description string `json:"description"`
-	Config      struct {
-		DefaultLeaseTTL int  `json:"default_lease_ttl"`
-		MaxLeaseTTL     int  `json:"max_lease_ttl"`
-		ForceNoCache    bool `json:"force_no_cache"`
-	} `json:"config"`
-}
+// https://www.vaultproject.io/docs/secrets/index.html
+type Mounts map[string]Mount
 
 func (c *client) ListMounts() (Mounts, error) {
 	// documentation is incorrect, must use the data field
@@ -186,6 +486,119 @@ func (c *client) ListMounts() (Mounts, error) {
 	return wrapper.Data, nil
 }
 
+func (c *client) EnableMount(path, mountType string, config MountConfig) error {
+	bs, err := json.Marshal(newEnableMount(mountType, config))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for enabling mount %q", path)
+	}
+
+	if err := c.post("/v1/sys/mounts/"+strings.Trim(path, "/"), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to enable mount %q at %q", mountType, path)
+	}
+
+	return nil
+}
+
+func (c *client) DisableMount(path string) error {
+	if err := c.delete("/v1/sys/mounts/" + strings.Trim(path, "/")); err != nil {
+		return errors.Wrapf(err, "failed to disable mount at %q", path)
+	}
+	return nil
+}
+
+func (c *client) TuneMount(path string, config MountConfig) error {
+	bs, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for tuning mount %q", path)
+	}
+
+	if err := c.post("/v1/sys/mounts/"+strings.Trim(path, "/")+"/tune", string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to tune mount at %q", path)
+	}
+
+	return nil
+}
+
+type mountConfigWrapper struct {
+	Data MountConfig `json:"data"`
+}
+
+func (c *client) ReadMountTune(path string) (MountConfig, error) {
+	var wrapper mountConfigWrapper
+	if err := c.get("/v1/sys/mounts/"+strings.Trim(path, "/")+"/tune", &wrapper); err != nil {
+		return MountConfig{}, errors.Wrapf(err, "failed to read tuning of mount at %q", path)
+	}
+	return wrapper.Data, nil
+}
+
+func (c *client) Remount(from, to string) error {
+	bs, err := json.Marshal(struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}{From: strings.Trim(from, "/"), To: strings.Trim(to, "/")})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for remounting %q", from)
+	}
+
+	if err := c.post("/v1/sys/remount", string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to remount %q to %q", from, to)
+	}
+
+	return nil
+}
+
+type authMountsWrapper struct {
+	Data AuthMounts `json:"data"`
+}
+
+// AuthMounts contains information about the auth methods currently
+// enabled in vault, keyed by the path at which each is mounted.
+//
+// More information can be found here:
+// https://www.vaultproject.io/docs/auth/index.html
+type AuthMounts map[string]Mount
+
+func (c *client) ListAuthMounts() (AuthMounts, error) {
+	var wrapper authMountsWrapper
+	if err := c.get("/v1/sys/auth", &wrapper); err != nil {
+		return nil, errors.Wrap(err, "failed to read auth mounts")
+	}
+	return wrapper.Data, nil
+}
+
+func (c *client) EnableAuth(path, authType string, config MountConfig) error {
+	bs, err := json.Marshal(newEnableMount(authType, config))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for enabling auth %q", path)
+	}
+
+	if err := c.post("/v1/sys/auth/"+strings.Trim(path, "/"), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to enable auth %q at %q", authType, path)
+	}
+
+	return nil
+}
+
+func (c *client) DisableAuth(path string) error {
+	if err := c.delete("/v1/sys/auth/" + strings.Trim(path, "/")); err != nil {
+		return errors.Wrapf(err, "failed to disable auth at %q", path)
+	}
+	return nil
+}
+
+func (c *client) TuneAuth(path string, config MountConfig) error {
+	bs, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for tuning auth %q", path)
+	}
+
+	if err := c.post("/v1/sys/auth/"+strings.Trim(path, "/")+"/tune", string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to tune auth at %q", path)
+	}
+
+	return nil
+}
+
 type listPolicies struct {
 	Policies []string `json:"policies"`
 }
@@ -240,10 +653,13 @@ func (c *client) DeletePolicy(name string) error {
 // More information about the vault seal mechanism can be found here:
 // https://www.vaultproject.io/docs/concepts/seal.html.
 type SealStatus struct {
+	Type        string `json:"type"`
+	Initialized bool   `json:"initialized"`
 	Sealed      bool   `json:"sealed"`
 	Threshold   int    `json:"t"`
 	Shares      int    `json:"n"`
 	Progress    int    `json:"progress"`
+	Nonce       string `json:"nonce"`
 	Version     string `json:"version"`
 	ClusterName string `json:"cluster_name"`
 	ClusterID   string `json:"cluster_id"`
@@ -1,7 +1,11 @@
 package vaultapi
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -101,3 +105,248 @@ func Test_Client_StepDown(t *testing.T) {
 	err := client.StepDown()
 	t.Log("step down error:", err)
 }
+
+func Test_Client_AuthMounts(t *testing.T) {
+	client := getClient(t, rootTokener)
+	hour, twoHours := time.Hour, 2*time.Hour
+
+	mounts, err := client.ListAuthMounts()
+	require.NoError(t, err)
+	require.Equal(t, "token", mounts["token/"].Type)
+	_, exists := mounts["userpass-test/"]
+	require.False(t, exists)
+
+	err = client.EnableAuth("userpass-test", "userpass", MountConfig{
+		Description:     "userpass for testing",
+		DefaultLeaseTTL: &hour,
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, client.DisableAuth("userpass-test"))
+	}()
+
+	mounts, err = client.ListAuthMounts()
+	require.NoError(t, err)
+	require.Equal(t, "userpass", mounts["userpass-test/"].Type)
+	require.Equal(t, "userpass for testing", mounts["userpass-test/"].Description)
+	require.Equal(t, hour, *mounts["userpass-test/"].Config.DefaultLeaseTTL)
+
+	err = client.TuneAuth("userpass-test/", MountConfig{
+		MaxLeaseTTL: &twoHours,
+	})
+	require.NoError(t, err)
+
+	mounts, err = client.ListAuthMounts()
+	require.NoError(t, err)
+	require.Equal(t, hour, *mounts["userpass-test/"].Config.DefaultLeaseTTL)
+	require.Equal(t, twoHours, *mounts["userpass-test/"].Config.MaxLeaseTTL)
+}
+
+func Test_Client_MountLifecycle(t *testing.T) {
+	client := getClient(t, rootTokener)
+	hour, twoHours, unauth := time.Hour, 2*time.Hour, "unauth"
+
+	err := client.EnableMount("kv-test", "kv", MountConfig{
+		Description:     "kv for testing",
+		DefaultLeaseTTL: &hour,
+		Options:         map[string]string{"version": "2"},
+	})
+	require.NoError(t, err)
+
+	mounts, err := client.ListMounts()
+	require.NoError(t, err)
+	require.Equal(t, "kv", mounts["kv-test/"].Type)
+	require.Equal(t, "kv for testing", mounts["kv-test/"].Description)
+	require.Equal(t, "2", mounts["kv-test/"].Options["version"])
+
+	err = client.TuneMount("kv-test", MountConfig{
+		MaxLeaseTTL:       &twoHours,
+		ListingVisibility: &unauth,
+	})
+	require.NoError(t, err)
+
+	config, err := client.ReadMountTune("kv-test/")
+	require.NoError(t, err)
+	require.Equal(t, hour, *config.DefaultLeaseTTL)
+	require.Equal(t, twoHours, *config.MaxLeaseTTL)
+	require.Equal(t, "unauth", *config.ListingVisibility)
+
+	// the mount can be tuned back to the defaults
+	zero, hidden, no := time.Duration(0), "", false
+	err = client.TuneMount("kv-test", MountConfig{
+		DefaultLeaseTTL:   &zero,
+		ListingVisibility: &hidden,
+		ForceNoCache:      &no,
+	})
+	require.NoError(t, err)
+
+	config, err = client.ReadMountTune("kv-test/")
+	require.NoError(t, err)
+	require.Equal(t, "", *config.ListingVisibility)
+	require.Equal(t, twoHours, *config.MaxLeaseTTL)
+
+	err = client.Remount("kv-test", "kv-test-moved")
+	require.NoError(t, err)
+
+	mounts, err = client.ListMounts()
+	require.NoError(t, err)
+	_, exists := mounts["kv-test/"]
+	require.False(t, exists)
+	require.Equal(t, "kv", mounts["kv-test-moved/"].Type)
+
+	err = client.DisableMount("kv-test-moved")
+	require.NoError(t, err)
+
+	_, err = client.ReadMountTune("kv-test-moved")
+	require.Error(t, err)
+}
+
+func Test_newEnableMount(t *testing.T) {
+	hour := time.Hour
+	bs, err := json.Marshal(newEnableMount("kv", MountConfig{
+		Description:     "kv for testing",
+		DefaultLeaseTTL: &hour,
+		Options:         map[string]string{"version": "2"},
+	}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "kv",
+		"description": "kv for testing",
+		"options": {"version": "2"},
+		"config": {"default_lease_ttl": "3600s"}
+	}`, string(bs))
+}
+
+func Test_MountConfig_JSON(t *testing.T) {
+	// nothing is sent for fields left unset
+	bs, err := json.Marshal(MountConfig{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(bs))
+
+	// fields can be set back to their defaults
+	zero, fractional, hidden, no := time.Duration(0), 1500*time.Millisecond, "", false
+	bs, err = json.Marshal(MountConfig{
+		DefaultLeaseTTL:         &zero,
+		MaxLeaseTTL:             &fractional,
+		ForceNoCache:            &no,
+		ListingVisibility:       &hidden,
+		AuditNonHMACRequestKeys: []string{},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"default_lease_ttl": "system",
+		"max_lease_ttl": "2s",
+		"force_no_cache": false,
+		"listing_visibility": "",
+		"audit_non_hmac_request_keys": []
+	}`, string(bs))
+
+	negative := -time.Second
+	_, err = json.Marshal(MountConfig{MaxLeaseTTL: &negative})
+	require.Error(t, err)
+
+	var config MountConfig
+	err = json.Unmarshal([]byte(`{"default_lease_ttl": 3600, "max_lease_ttl": 0, "force_no_cache": true}`), &config)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, *config.DefaultLeaseTTL)
+	require.Equal(t, time.Duration(0), *config.MaxLeaseTTL)
+	require.True(t, *config.ForceNoCache)
+}
+
+func Test_Client_Leases(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	before, err := client.ListLeases("auth/token/create/")
+	require.NoError(t, err)
+
+	token, err := client.CreateToken(TokenOptions{
+		Policies:    []string{"default"},
+		Orphan:      true,
+		Renewable:   true,
+		DisplayName: "lease-test",
+	})
+	require.NoError(t, err)
+
+	after, err := client.ListLeases("auth/token/create/")
+	require.NoError(t, err)
+	require.Equal(t, len(before)+1, len(after))
+
+	// find the lease that was created along with the token
+	var leaseID string
+	for _, key := range after {
+		found := false
+		for _, existing := range before {
+			if key == existing {
+				found = true
+			}
+		}
+		if !found {
+			leaseID = "auth/token/create/" + key
+		}
+	}
+	require.NotEmpty(t, leaseID)
+
+	lease, err := client.LookupLease(leaseID)
+	require.NoError(t, err)
+	require.Equal(t, leaseID, lease.ID)
+	require.True(t, lease.Renewable)
+	require.False(t, lease.IssueTime.IsZero())
+	require.True(t, lease.ExpireTime.After(lease.IssueTime))
+
+	renewed, err := client.RenewLease(leaseID, 1*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, leaseID, renewed.ID)
+	require.True(t, renewed.LeaseDuration > 0)
+
+	err = client.RevokeLease(leaseID)
+	require.NoError(t, err)
+
+	_, err = client.LookupToken(token.ID)
+	require.Error(t, err)
+
+	_, err = client.LookupLease(leaseID)
+	require.Error(t, err)
+}
+
+func Test_Client_ListLeases_notFound(t *testing.T) {
+	// vault responds with not found to a prefix without leases
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	leases, err := client.ListLeases("auth/token/create/")
+	require.NoError(t, err)
+	require.Empty(t, leases)
+}
+
+func Test_Client_ServerHealth(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	health, err := client.ServerHealth("http://localhost:8200", HealthOptions{
+		StandbyOK:  true,
+		SealedCode: 200,
+	})
+	require.NoError(t, err)
+	require.True(t, health.Initialized)
+	require.False(t, health.Standby)
+	require.Equal(t, 200, health.StatusCode)
+
+	_, err = client.ServerHealth("http://localhost:1", HealthOptions{})
+	require.Error(t, err)
+}
+
+func Test_Client_ServersHealth(t *testing.T) {
+	opts := devOpts()
+	opts.Servers = append(opts.Servers, "http://localhost:1")
+	client, err := New(opts, rootTokener())
+	require.NoError(t, err)
+
+	healths, err := client.ServersHealth(HealthOptions{})
+	require.Error(t, err)
+	require.Len(t, healths, 1)
+	require.False(t, healths["http://localhost:8200"].Sealed)
+}
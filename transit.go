@@ -0,0 +1,723 @@
+package vaultapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//go:generate go run github.com/shoenig/mockery3/v3/cmd/mockery3 -interface Transit -package vaultapitest
+
+// A Transit provides encryption as a service through the transit secrets
+// engine. Plaintexts, contexts and inputs are the raw bytes to be operated
+// on; encoding them as base64 for vault is done by the Transit.
+//
+// More information about the transit secrets engine can be found here:
+// https://www.vaultproject.io/api/secret/transit/index.html
+type Transit interface {
+	// Key Management
+	CreateKey(name string, opts TransitKeyOptions) error
+	ReadKey(name string) (TransitKey, error)
+	ListKeys() ([]string, error)
+	DeleteKey(name string) error
+	RotateKey(name string) error
+	ConfigKey(name string, update func(*TransitKeyConfig)) error
+	ExportKey(name, keyType string, version int) (map[int]string, error)
+	TrimKey(name string, minAvailableVersion int) error
+
+	// Encryption
+	Encrypt(key string, plaintext []byte, opts ...TransitOption) (string, error)
+	Decrypt(key, ciphertext string, opts ...TransitOption) ([]byte, error)
+	Rewrap(key, ciphertext string, opts ...TransitOption) (string, error)
+	EncryptBatch(key string, items []TransitBatchItem) ([]TransitBatchResult, error)
+	DecryptBatch(key string, items []TransitBatchItem) ([]TransitBatchResult, error)
+	RewrapBatch(key string, items []TransitBatchItem) ([]TransitBatchResult, error)
+	GenerateDataKey(key string, bits int, plaintext bool, opts ...TransitOption) (TransitDataKey, error)
+
+	// Signing and Hashing
+	HMAC(key string, input []byte, opts ...TransitOption) (string, error)
+	VerifyHMAC(key string, input []byte, hmac string, opts ...TransitOption) (bool, error)
+	Sign(key string, input []byte, opts ...TransitOption) (string, error)
+	Verify(key string, input []byte, signature string, opts ...TransitOption) (bool, error)
+	Hash(input []byte, opts ...TransitOption) (string, error)
+}
+
+// The types of key supported by the transit secrets engine.
+const (
+	TransitKeyAES128GCM96      = "aes128-gcm96"
+	TransitKeyAES256GCM96      = "aes256-gcm96"
+	TransitKeyChaCha20Poly1305 = "chacha20-poly1305"
+	TransitKeyED25519          = "ed25519"
+	TransitKeyECDSAP256        = "ecdsa-p256"
+	TransitKeyECDSAP384        = "ecdsa-p384"
+	TransitKeyECDSAP521        = "ecdsa-p521"
+	TransitKeyRSA2048          = "rsa-2048"
+	TransitKeyRSA3072          = "rsa-3072"
+	TransitKeyRSA4096          = "rsa-4096"
+)
+
+// The kinds of key which may be exported with ExportKey.
+const (
+	TransitExportEncryptionKey = "encryption-key"
+	TransitExportSigningKey    = "signing-key"
+	TransitExportHMACKey       = "hmac-key"
+)
+
+// TransitKeyOptions are used to configure a transit key upon creation.
+type TransitKeyOptions struct {
+	// Type is the type of key to create, which defaults
+	// to TransitKeyAES256GCM96 if not set.
+	Type                 string `json:"type,omitempty"`
+	Derived              bool   `json:"derived,omitempty"`
+	ConvergentEncryption bool   `json:"convergent_encryption,omitempty"`
+	Exportable           bool   `json:"exportable,omitempty"`
+	AllowPlaintextBackup bool   `json:"allow_plaintext_backup,omitempty"`
+}
+
+// A TransitKeyVersion describes one version of a transit key. The PublicKey
+// and Name are only set for asymmetric keys.
+type TransitKeyVersion struct {
+	CreationTime time.Time `json:"creation_time"`
+	PublicKey    string    `json:"public_key"`
+	Name         string    `json:"name"`
+}
+
+// A TransitKey describes a named key of the transit secrets engine.
+type TransitKey struct {
+	Name                 string
+	Type                 string
+	Derived              bool
+	Exportable           bool
+	AllowPlaintextBackup bool
+	DeletionAllowed      bool
+	LatestVersion        int
+	MinAvailableVersion  int
+	MinDecryptionVersion int
+	MinEncryptionVersion int
+	SupportsEncryption   bool
+	SupportsDecryption   bool
+	SupportsDerivation   bool
+	SupportsSigning      bool
+	Versions             map[int]TransitKeyVersion
+}
+
+type transitKeyJSON struct {
+	Name                 string                     `json:"name"`
+	Type                 string                     `json:"type"`
+	Derived              bool                       `json:"derived"`
+	Exportable           bool                       `json:"exportable"`
+	AllowPlaintextBackup bool                       `json:"allow_plaintext_backup"`
+	DeletionAllowed      bool                       `json:"deletion_allowed"`
+	LatestVersion        int                        `json:"latest_version"`
+	MinAvailableVersion  int                        `json:"min_available_version"`
+	MinDecryptionVersion int                        `json:"min_decryption_version"`
+	MinEncryptionVersion int                        `json:"min_encryption_version"`
+	SupportsEncryption   bool                       `json:"supports_encryption"`
+	SupportsDecryption   bool                       `json:"supports_decryption"`
+	SupportsDerivation   bool                       `json:"supports_derivation"`
+	SupportsSigning      bool                       `json:"supports_signing"`
+	Keys                 map[string]json.RawMessage `json:"keys"`
+}
+
+// UnmarshalJSON decodes tk from the form returned by vault, in which the
+// versions of symmetric keys are only the unix time of their creation.
+func (tk *TransitKey) UnmarshalJSON(bs []byte) error {
+	var response transitKeyJSON
+	if err := json.Unmarshal(bs, &response); err != nil {
+		return err
+	}
+
+	versions := make(map[int]TransitKeyVersion, len(response.Keys))
+	for number, raw := range response.Keys {
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return errors.Errorf("invalid key version %q", number)
+		}
+
+		var created int64
+		if err := json.Unmarshal(raw, &created); err == nil {
+			versions[version] = TransitKeyVersion{CreationTime: time.Unix(created, 0)}
+			continue
+		}
+
+		var asymmetric TransitKeyVersion
+		if err := json.Unmarshal(raw, &asymmetric); err != nil {
+			return errors.Wrapf(err, "failed to parse key version %d", version)
+		}
+		versions[version] = asymmetric
+	}
+
+	*tk = TransitKey{
+		Name:                 response.Name,
+		Type:                 response.Type,
+		Derived:              response.Derived,
+		Exportable:           response.Exportable,
+		AllowPlaintextBackup: response.AllowPlaintextBackup,
+		DeletionAllowed:      response.DeletionAllowed,
+		LatestVersion:        response.LatestVersion,
+		MinAvailableVersion:  response.MinAvailableVersion,
+		MinDecryptionVersion: response.MinDecryptionVersion,
+		MinEncryptionVersion: response.MinEncryptionVersion,
+		SupportsEncryption:   response.SupportsEncryption,
+		SupportsDecryption:   response.SupportsDecryption,
+		SupportsDerivation:   response.SupportsDerivation,
+		SupportsSigning:      response.SupportsSigning,
+		Versions:             versions,
+	}
+	return nil
+}
+
+// TransitKeyConfig contains the configurable settings of a transit key.
+// Note that vault does not allow Exportable or AllowPlaintextBackup to be
+// disabled once they have been enabled.
+type TransitKeyConfig struct {
+	MinDecryptionVersion int  `json:"min_decryption_version"`
+	MinEncryptionVersion int  `json:"min_encryption_version"`
+	DeletionAllowed      bool `json:"deletion_allowed"`
+	Exportable           bool `json:"exportable"`
+	AllowPlaintextBackup bool `json:"allow_plaintext_backup"`
+}
+
+// A TransitOption sets an optional parameter of a transit request.
+// Options which do not apply to a request are ignored by vault.
+type TransitOption func(map[string]interface{})
+
+// WithDerivationContext is a TransitOption which sets the context used
+// for key derivation, which is required when using a derived key.
+func WithDerivationContext(context []byte) TransitOption {
+	return func(body map[string]interface{}) {
+		body["context"] = base64.StdEncoding.EncodeToString(context)
+	}
+}
+
+// WithKeyVersion is a TransitOption which sets the version of the key
+// to use, rather than the latest version.
+func WithKeyVersion(version int) TransitOption {
+	return func(body map[string]interface{}) {
+		body["key_version"] = version
+	}
+}
+
+// WithHashAlgorithm is a TransitOption which sets the hash algorithm used
+// for hashing, HMAC, and signing, e.g. "sha2-256" or "sha2-512". The hash
+// and HMAC endpoints take the algorithm as part of their path, which is
+// where it is sent for Hash, HMAC, and VerifyHMAC.
+func WithHashAlgorithm(algorithm string) TransitOption {
+	return func(body map[string]interface{}) {
+		body["hash_algorithm"] = algorithm
+	}
+}
+
+// WithSignatureAlgorithm is a TransitOption which sets the signature
+// algorithm used when signing with an RSA key, "pss" or "pkcs1v15".
+func WithSignatureAlgorithm(algorithm string) TransitOption {
+	return func(body map[string]interface{}) {
+		body["signature_algorithm"] = algorithm
+	}
+}
+
+// WithMarshalingAlgorithm is a TransitOption which sets how ECDSA
+// signatures are encoded, "asn1" or "jws".
+func WithMarshalingAlgorithm(algorithm string) TransitOption {
+	return func(body map[string]interface{}) {
+		body["marshaling_algorithm"] = algorithm
+	}
+}
+
+// WithPrehashed is a TransitOption which indicates the input to be signed
+// or verified has already been hashed with the configured hash algorithm.
+func WithPrehashed() TransitOption {
+	return func(body map[string]interface{}) {
+		body["prehashed"] = true
+	}
+}
+
+// A TransitBatchItem is one element of a batch request. Plaintext is used
+// when encrypting, and Ciphertext when decrypting or rewrapping.
+type TransitBatchItem struct {
+	Plaintext  []byte
+	Ciphertext string
+	Context    []byte
+	KeyVersion int
+}
+
+type transitBatchItemJSON struct {
+	Plaintext  *string `json:"plaintext,omitempty"`
+	Ciphertext string  `json:"ciphertext,omitempty"`
+	Context    string  `json:"context,omitempty"`
+	KeyVersion int     `json:"key_version,omitempty"`
+}
+
+// A TransitBatchResult is the result of one element of a batch request,
+// in the same order as the items of the request. If the item could not be
+// processed, Error describes why.
+type TransitBatchResult struct {
+	Plaintext  []byte
+	Ciphertext string
+	KeyVersion int
+	Error      string
+}
+
+type transitBatchResultJSON struct {
+	Plaintext  string `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+	KeyVersion int    `json:"key_version"`
+	Error      string `json:"error"`
+}
+
+// A TransitDataKey is a newly generated data key, which is returned by
+// vault encrypted by a transit key, and optionally also in plaintext.
+type TransitDataKey struct {
+	Plaintext  []byte
+	Ciphertext string
+	KeyVersion int
+}
+
+type transit struct {
+	client *client
+	mount  string
+}
+
+// Transit returns a Transit for the transit secrets engine enabled at
+// mount, which is "transit" if mount is empty.
+func (c *client) Transit(mount string) Transit {
+	mount = strings.Trim(mount, "/")
+	if mount == "" {
+		mount = "transit"
+	}
+	return &transit{client: c, mount: mount}
+}
+
+func (t *transit) path(elements ...string) string {
+	return "/v1/" + t.mount + "/" + strings.Join(elements, "/")
+}
+
+func (t *transit) post(path string, body map[string]interface{}, opts []TransitOption, i interface{}) error {
+	for _, opt := range opts {
+		opt(body)
+	}
+
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create json for transit request")
+	}
+
+	return t.client.post(path, string(bs), i)
+}
+
+func (t *transit) CreateKey(name string, opts TransitKeyOptions) error {
+	bs, err := json.Marshal(opts)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for creating key %q", name)
+	}
+
+	if err := t.client.post(t.path("keys", name), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to create transit key %q", name)
+	}
+
+	return nil
+}
+
+type transitKeyWrapper struct {
+	Data TransitKey `json:"data"`
+}
+
+func (t *transit) ReadKey(name string) (TransitKey, error) {
+	var wrapper transitKeyWrapper
+	if err := t.client.get(t.path("keys", name), &wrapper); err != nil {
+		return TransitKey{}, errors.Wrapf(err, "failed to read transit key %q", name)
+	}
+	return wrapper.Data, nil
+}
+
+func (t *transit) ListKeys() ([]string, error) {
+	var data keysData
+	if err := t.client.list(t.path("keys"), &data); err != nil {
+		return nil, errors.Wrap(err, "failed to list transit keys")
+	}
+	keys := data.Data["keys"]
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// DeleteKey deletes the key called name, which is only
+// allowed if the key is configured with DeletionAllowed.
+func (t *transit) DeleteKey(name string) error {
+	if err := t.client.deleteKey(t.path("keys", name)); err != nil {
+		return errors.Wrapf(err, "failed to delete transit key %q", name)
+	}
+	return nil
+}
+
+func (t *transit) RotateKey(name string) error {
+	if err := t.client.post(t.path("keys", name, "rotate"), "", nil); err != nil {
+		return errors.Wrapf(err, "failed to rotate transit key %q", name)
+	}
+	return nil
+}
+
+// ConfigKey reads the current configuration of the key called name,
+// applies update, and writes the resulting configuration.
+func (t *transit) ConfigKey(name string, update func(*TransitKeyConfig)) error {
+	key, err := t.ReadKey(name)
+	if err != nil {
+		return err
+	}
+
+	config := TransitKeyConfig{
+		MinDecryptionVersion: key.MinDecryptionVersion,
+		MinEncryptionVersion: key.MinEncryptionVersion,
+		DeletionAllowed:      key.DeletionAllowed,
+		Exportable:           key.Exportable,
+		AllowPlaintextBackup: key.AllowPlaintextBackup,
+	}
+	update(&config)
+
+	bs, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create json for configuring key %q", name)
+	}
+
+	if err := t.client.post(t.path("keys", name, "config"), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to configure transit key %q", name)
+	}
+
+	return nil
+}
+
+type transitExportWrapper struct {
+	Data struct {
+		Keys map[string]string `json:"keys"`
+	} `json:"data"`
+}
+
+// ExportKey returns the key material of the key called name, keyed by
+// version. If version is 0, every version of the key is exported.
+func (t *transit) ExportKey(name, keyType string, version int) (map[int]string, error) {
+	path := t.path("export", keyType, name)
+	if version > 0 {
+		path = t.path("export", keyType, name, strconv.Itoa(version))
+	}
+
+	var wrapper transitExportWrapper
+	if err := t.client.get(path, &wrapper); err != nil {
+		return nil, errors.Wrapf(err, "failed to export %s of transit key %q", keyType, name)
+	}
+
+	keys := make(map[int]string, len(wrapper.Data.Keys))
+	for number, material := range wrapper.Data.Keys {
+		v, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, errors.Errorf("invalid key version %q", number)
+		}
+		keys[v] = material
+	}
+
+	return keys, nil
+}
+
+func (t *transit) TrimKey(name string, minAvailableVersion int) error {
+	bs, err := json.Marshal(struct {
+		MinAvailableVersion int `json:"min_available_version"`
+	}{MinAvailableVersion: minAvailableVersion})
+	if err != nil {
+		return err
+	}
+
+	if err := t.client.post(t.path("keys", name, "trim"), string(bs), nil); err != nil {
+		return errors.Wrapf(err, "failed to trim transit key %q", name)
+	}
+
+	return nil
+}
+
+type transitCiphertextWrapper struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+}
+
+func (t *transit) Encrypt(key string, plaintext []byte, opts ...TransitOption) (string, error) {
+	body := map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	}
+
+	var wrapper transitCiphertextWrapper
+	if err := t.post(t.path("encrypt", key), body, opts, &wrapper); err != nil {
+		// do not provide plaintext anywhere
+		return "", errors.Wrapf(err, "failed to encrypt with transit key %q", key)
+	}
+
+	return wrapper.Data.Ciphertext, nil
+}
+
+type transitPlaintextWrapper struct {
+	Data struct {
+		Plaintext string `json:"plaintext"`
+	} `json:"data"`
+}
+
+func (t *transit) Decrypt(key, ciphertext string, opts ...TransitOption) ([]byte, error) {
+	body := map[string]interface{}{
+		"ciphertext": ciphertext,
+	}
+
+	var wrapper transitPlaintextWrapper
+	if err := t.post(t.path("decrypt", key), body, opts, &wrapper); err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt with transit key %q", key)
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(wrapper.Data.Plaintext)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode plaintext")
+	}
+
+	return plaintext, nil
+}
+
+// Rewrap re-encrypts ciphertext with the latest version of the key,
+// without exposing the plaintext.
+func (t *transit) Rewrap(key, ciphertext string, opts ...TransitOption) (string, error) {
+	body := map[string]interface{}{
+		"ciphertext": ciphertext,
+	}
+
+	var wrapper transitCiphertextWrapper
+	if err := t.post(t.path("rewrap", key), body, opts, &wrapper); err != nil {
+		return "", errors.Wrapf(err, "failed to rewrap with transit key %q", key)
+	}
+
+	return wrapper.Data.Ciphertext, nil
+}
+
+type transitBatchWrapper struct {
+	Data struct {
+		BatchResults []transitBatchResultJSON `json:"batch_results"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (t *transit) batch(operation, key string, items []TransitBatchItem) ([]TransitBatchResult, error) {
+	input := make([]transitBatchItemJSON, 0, len(items))
+	for _, item := range items {
+		encoded := transitBatchItemJSON{
+			Ciphertext: item.Ciphertext,
+			KeyVersion: item.KeyVersion,
+		}
+		if item.Plaintext != nil {
+			// an empty plaintext is still sent, so that it is encrypted
+			plaintext := base64.StdEncoding.EncodeToString(item.Plaintext)
+			encoded.Plaintext = &plaintext
+		}
+		if item.Context != nil {
+			encoded.Context = base64.StdEncoding.EncodeToString(item.Context)
+		}
+		input = append(input, encoded)
+	}
+
+	bs, err := json.Marshal(map[string]interface{}{
+		"batch_input": input,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create json for transit request")
+	}
+
+	// vault responds with 400 if any item fails, along with
+	// the results of every item
+	var wrapper transitBatchWrapper
+	if err := t.client.postPartial(t.path(operation, key), string(bs), &wrapper); err != nil {
+		return nil, errors.Wrapf(err, "failed to batch %s with transit key %q", operation, key)
+	}
+
+	if len(wrapper.Data.BatchResults) == 0 && len(wrapper.Errors) > 0 {
+		return nil, errors.Errorf("failed to batch %s with transit key %q: %s", operation, key, strings.Join(wrapper.Errors, ", "))
+	}
+
+	if len(wrapper.Data.BatchResults) != len(items) {
+		return nil, errors.Errorf("expected %d batch results, got %d", len(items), len(wrapper.Data.BatchResults))
+	}
+
+	results := make([]TransitBatchResult, 0, len(items))
+	for _, result := range wrapper.Data.BatchResults {
+		decoded := TransitBatchResult{
+			Ciphertext: result.Ciphertext,
+			KeyVersion: result.KeyVersion,
+			Error:      result.Error,
+		}
+		if result.Plaintext != "" {
+			plaintext, err := base64.StdEncoding.DecodeString(result.Plaintext)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decode plaintext")
+			}
+			decoded.Plaintext = plaintext
+		}
+		results = append(results, decoded)
+	}
+
+	return results, nil
+}
+
+func (t *transit) EncryptBatch(key string, items []TransitBatchItem) ([]TransitBatchResult, error) {
+	return t.batch("encrypt", key, items)
+}
+
+func (t *transit) DecryptBatch(key string, items []TransitBatchItem) ([]TransitBatchResult, error) {
+	return t.batch("decrypt", key, items)
+}
+
+func (t *transit) RewrapBatch(key string, items []TransitBatchItem) ([]TransitBatchResult, error) {
+	return t.batch("rewrap", key, items)
+}
+
+type transitDataKeyWrapper struct {
+	Data struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+		KeyVersion int    `json:"key_version"`
+	} `json:"data"`
+}
+
+// GenerateDataKey creates a new data key of bits length (or 256 if bits is
+// 0), encrypted with key. The plaintext of the data key is only returned if
+// plaintext is set.
+func (t *transit) GenerateDataKey(key string, bits int, plaintext bool, opts ...TransitOption) (TransitDataKey, error) {
+	kind := "wrapped"
+	if plaintext {
+		kind = "plaintext"
+	}
+
+	body := make(map[string]interface{})
+	if bits > 0 {
+		body["bits"] = bits
+	}
+
+	var wrapper transitDataKeyWrapper
+	if err := t.post(t.path("datakey", kind, key), body, opts, &wrapper); err != nil {
+		return TransitDataKey{}, errors.Wrapf(err, "failed to generate data key with transit key %q", key)
+	}
+
+	dataKey := TransitDataKey{
+		Ciphertext: wrapper.Data.Ciphertext,
+		KeyVersion: wrapper.Data.KeyVersion,
+	}
+
+	if plaintext {
+		decoded, err := base64.StdEncoding.DecodeString(wrapper.Data.Plaintext)
+		if err != nil {
+			return TransitDataKey{}, errors.Wrap(err, "failed to decode data key")
+		}
+		dataKey.Plaintext = decoded
+	}
+
+	return dataKey, nil
+}
+
+// algorithmPath applies opts to body, and returns the path made of elements
+// followed by the hash algorithm set with WithHashAlgorithm, if any
+func (t *transit) algorithmPath(body map[string]interface{}, opts []TransitOption, elements ...string) string {
+	for _, opt := range opts {
+		opt(body)
+	}
+
+	if algorithm, ok := body["hash_algorithm"].(string); ok {
+		delete(body, "hash_algorithm")
+		elements = append(elements, algorithm)
+	}
+
+	return t.path(elements...)
+}
+
+type transitVerifyWrapper struct {
+	Data struct {
+		Valid bool `json:"valid"`
+	} `json:"data"`
+}
+
+func (t *transit) HMAC(key string, input []byte, opts ...TransitOption) (string, error) {
+	body := map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(input),
+	}
+
+	var wrapper struct {
+		Data struct {
+			HMAC string `json:"hmac"`
+		} `json:"data"`
+	}
+	path := t.algorithmPath(body, opts, "hmac", key)
+	if err := t.post(path, body, nil, &wrapper); err != nil {
+		return "", errors.Wrapf(err, "failed to hmac with transit key %q", key)
+	}
+
+	return wrapper.Data.HMAC, nil
+}
+
+func (t *transit) VerifyHMAC(key string, input []byte, hmac string, opts ...TransitOption) (bool, error) {
+	body := map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(input),
+		"hmac":  hmac,
+	}
+
+	var wrapper transitVerifyWrapper
+	path := t.algorithmPath(body, opts, "verify", key)
+	if err := t.post(path, body, nil, &wrapper); err != nil {
+		return false, errors.Wrapf(err, "failed to verify hmac with transit key %q", key)
+	}
+
+	return wrapper.Data.Valid, nil
+}
+
+func (t *transit) Sign(key string, input []byte, opts ...TransitOption) (string, error) {
+	body := map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(input),
+	}
+
+	var wrapper struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := t.post(t.path("sign", key), body, opts, &wrapper); err != nil {
+		return "", errors.Wrapf(err, "failed to sign with transit key %q", key)
+	}
+
+	return wrapper.Data.Signature, nil
+}
+
+func (t *transit) Verify(key string, input []byte, signature string, opts ...TransitOption) (bool, error) {
+	body := map[string]interface{}{
+		"input":     base64.StdEncoding.EncodeToString(input),
+		"signature": signature,
+	}
+
+	var wrapper transitVerifyWrapper
+	if err := t.post(t.path("verify", key), body, opts, &wrapper); err != nil {
+		return false, errors.Wrapf(err, "failed to verify signature with transit key %q", key)
+	}
+
+	return wrapper.Data.Valid, nil
+}
+
+// Hash returns the hex encoded hash of input, using sha2-256 unless
+// another algorithm is set with WithHashAlgorithm.
+func (t *transit) Hash(input []byte, opts ...TransitOption) (string, error) {
+	body := map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(input),
+	}
+
+	var wrapper struct {
+		Data struct {
+			Sum string `json:"sum"`
+		} `json:"data"`
+	}
+	path := t.algorithmPath(body, opts, "hash")
+	if err := t.post(path, body, nil, &wrapper); err != nil {
+		return "", errors.Wrap(err, "failed to hash with transit")
+	}
+
+	return wrapper.Data.Sum, nil
+}
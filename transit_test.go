@@ -0,0 +1,307 @@
+package vaultapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// getTransit enables the transit secrets engine in the dev vault,
+// if it is not already enabled
+func getTransit(t *testing.T) Transit {
+	client := getClient(t, rootTokener)
+
+	mounts, err := client.ListMounts()
+	require.NoError(t, err)
+	if _, exists := mounts["transit/"]; !exists {
+		err := client.EnableMount("transit", "transit", MountConfig{})
+		require.NoError(t, err)
+	}
+
+	return client.Transit("")
+}
+
+// deleteTransitKey allows deleting the transit key, then deletes it
+func deleteTransitKey(t *testing.T, transit Transit, name string) {
+	err := transit.ConfigKey(name, func(config *TransitKeyConfig) {
+		config.DeletionAllowed = true
+	})
+	require.NoError(t, err)
+	require.NoError(t, transit.DeleteKey(name))
+}
+
+func Test_TransitKey_UnmarshalJSON(t *testing.T) {
+	var symmetric TransitKey
+	err := json.Unmarshal([]byte(`{
+		"name": "foo",
+		"type": "aes256-gcm96",
+		"latest_version": 2,
+		"min_decryption_version": 1,
+		"supports_encryption": true,
+		"keys": {"1": 1600000000, "2": 1600000060}
+	}`), &symmetric)
+	require.NoError(t, err)
+	require.Equal(t, "foo", symmetric.Name)
+	require.Equal(t, 2, symmetric.LatestVersion)
+	require.True(t, symmetric.SupportsEncryption)
+	require.Equal(t, time.Unix(1600000060, 0), symmetric.Versions[2].CreationTime)
+
+	var asymmetric TransitKey
+	err = json.Unmarshal([]byte(`{
+		"name": "bar",
+		"type": "ecdsa-p256",
+		"keys": {"1": {"creation_time": "2020-09-13T12:26:40Z", "name": "P-256", "public_key": "-----BEGIN PUBLIC KEY-----"}}
+	}`), &asymmetric)
+	require.NoError(t, err)
+	require.Equal(t, "P-256", asymmetric.Versions[1].Name)
+	require.Equal(t, "-----BEGIN PUBLIC KEY-----", asymmetric.Versions[1].PublicKey)
+	require.Equal(t, 2020, asymmetric.Versions[1].CreationTime.Year())
+}
+
+func Test_Transit_Encryption(t *testing.T) {
+	transit := getTransit(t)
+
+	err := transit.CreateKey("test-enc", TransitKeyOptions{Exportable: true})
+	require.NoError(t, err)
+	defer deleteTransitKey(t, transit, "test-enc")
+
+	keys, err := transit.ListKeys()
+	require.NoError(t, err)
+	require.Contains(t, keys, "test-enc")
+
+	ciphertext, err := transit.Encrypt("test-enc", []byte("hunter2"))
+	require.NoError(t, err)
+	require.Contains(t, ciphertext, "vault:v1:")
+
+	plaintext, err := transit.Decrypt("test-enc", ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hunter2"), plaintext)
+
+	require.NoError(t, transit.RotateKey("test-enc"))
+
+	rewrapped, err := transit.Rewrap("test-enc", ciphertext)
+	require.NoError(t, err)
+	require.Contains(t, rewrapped, "vault:v2:")
+
+	results, err := transit.EncryptBatch("test-enc", []TransitBatchItem{
+		{Plaintext: []byte("one")},
+		{Plaintext: []byte("two")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(results))
+
+	results, err = transit.DecryptBatch("test-enc", []TransitBatchItem{
+		{Ciphertext: results[0].Ciphertext},
+		{Ciphertext: results[1].Ciphertext},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), results[0].Plaintext)
+	require.Equal(t, []byte("two"), results[1].Plaintext)
+
+	dataKey, err := transit.GenerateDataKey("test-enc", 0, true)
+	require.NoError(t, err)
+	require.Equal(t, 32, len(dataKey.Plaintext))
+
+	unwrapped, err := transit.Decrypt("test-enc", dataKey.Ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, dataKey.Plaintext, unwrapped)
+
+	exported, err := transit.ExportKey("test-enc", TransitExportEncryptionKey, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(exported))
+
+	err = transit.ConfigKey("test-enc", func(config *TransitKeyConfig) {
+		config.MinDecryptionVersion = 2
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, transit.TrimKey("test-enc", 2))
+
+	key, err := transit.ReadKey("test-enc")
+	require.NoError(t, err)
+	require.Equal(t, 2, key.LatestVersion)
+	require.Equal(t, 2, key.MinAvailableVersion)
+	require.Equal(t, 1, len(key.Versions))
+}
+
+func Test_Transit_Signing(t *testing.T) {
+	transit := getTransit(t)
+
+	err := transit.CreateKey("test-sign", TransitKeyOptions{Type: TransitKeyECDSAP256})
+	require.NoError(t, err)
+	defer deleteTransitKey(t, transit, "test-sign")
+
+	signature, err := transit.Sign("test-sign", []byte("message"), WithHashAlgorithm("sha2-256"))
+	require.NoError(t, err)
+
+	valid, err := transit.Verify("test-sign", []byte("message"), signature, WithHashAlgorithm("sha2-256"))
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	valid, err = transit.Verify("test-sign", []byte("forged"), signature, WithHashAlgorithm("sha2-256"))
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	hmac, err := transit.HMAC("test-sign", []byte("message"))
+	require.NoError(t, err)
+
+	valid, err = transit.VerifyHMAC("test-sign", []byte("message"), hmac)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	sum, err := transit.Hash([]byte("message"))
+	require.NoError(t, err)
+	require.Equal(t, "ab530a13e45914982b79f9b7e3fba994cfd1f3fb22f71cea1afbf02b460c6d1d", sum)
+
+	sum, err = transit.Hash([]byte("message"), WithHashAlgorithm("sha2-512"))
+	require.NoError(t, err)
+	expected := sha512.Sum512([]byte("message"))
+	require.Equal(t, hex.EncodeToString(expected[:]), sum)
+}
+
+func Test_Transit_HMAC_algorithm(t *testing.T) {
+	transit := getTransit(t)
+
+	err := transit.CreateKey("test-hmac", TransitKeyOptions{Exportable: true})
+	require.NoError(t, err)
+	defer deleteTransitKey(t, transit, "test-hmac")
+
+	exported, err := transit.ExportKey("test-hmac", TransitExportHMACKey, 1)
+	require.NoError(t, err)
+	key, err := base64.StdEncoding.DecodeString(exported[1])
+	require.NoError(t, err)
+
+	mac := hmac.New(sha512.New, key)
+	_, _ = mac.Write([]byte("message"))
+	expected := "vault:v1:" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	sum, err := transit.HMAC("test-hmac", []byte("message"), WithHashAlgorithm("sha2-512"))
+	require.NoError(t, err)
+	require.Equal(t, expected, sum)
+
+	valid, err := transit.VerifyHMAC("test-hmac", []byte("message"), sum, WithHashAlgorithm("sha2-512"))
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	valid, err = transit.VerifyHMAC("test-hmac", []byte("message"), sum)
+	require.NoError(t, err)
+	require.False(t, valid)
+}
+
+// transitRequest is a request received by transitServer
+type transitRequest struct {
+	path string
+	body map[string]interface{}
+}
+
+// transitServer pretends to be vault, responding to every request with
+// status and response, and recording the requests it receives
+func transitServer(status int, response string) (*httptest.Server, func() []transitRequest) {
+	var lock sync.Mutex
+	var requests []transitRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		lock.Lock()
+		requests = append(requests, transitRequest{path: r.URL.Path, body: body})
+		lock.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+
+	return server, func() []transitRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return requests
+	}
+}
+
+func Test_Transit_hashAlgorithm_paths(t *testing.T) {
+	server, requests := transitServer(http.StatusOK, `{"data": {}}`)
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+	transit := client.Transit("")
+
+	_, err = transit.Hash([]byte("message"), WithHashAlgorithm("sha2-512"))
+	require.NoError(t, err)
+	_, err = transit.HMAC("k", []byte("message"), WithHashAlgorithm("sha2-512"))
+	require.NoError(t, err)
+	_, err = transit.VerifyHMAC("k", []byte("message"), "vault:v1:abc", WithHashAlgorithm("sha2-512"))
+	require.NoError(t, err)
+	_, err = transit.Sign("k", []byte("message"), WithHashAlgorithm("sha2-512"))
+	require.NoError(t, err)
+	_, err = transit.HMAC("k", []byte("message"))
+	require.NoError(t, err)
+
+	received := requests()
+	require.Equal(t, 5, len(received))
+
+	for i, path := range []string{
+		"/v1/transit/hash/sha2-512",
+		"/v1/transit/hmac/k/sha2-512",
+		"/v1/transit/verify/k/sha2-512",
+		"/v1/transit/sign/k",
+		"/v1/transit/hmac/k",
+	} {
+		require.Equal(t, path, received[i].path)
+	}
+
+	require.NotContains(t, received[0].body, "hash_algorithm")
+	require.NotContains(t, received[1].body, "hash_algorithm")
+	require.Equal(t, "sha2-512", received[3].body["hash_algorithm"])
+}
+
+func Test_Transit_EncryptBatch_partial(t *testing.T) {
+	server, requests := transitServer(http.StatusBadRequest, `{
+		"data": {"batch_results": [
+			{"ciphertext": "vault:v1:abc", "key_version": 1},
+			{"error": "failed to decode context"}
+		]}
+	}`)
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	results, err := client.Transit("").EncryptBatch("k", []TransitBatchItem{
+		{Plaintext: []byte{}},
+		{Plaintext: []byte("two"), Context: []byte("ctx")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []TransitBatchResult{
+		{Ciphertext: "vault:v1:abc", KeyVersion: 1},
+		{Error: "failed to decode context"},
+	}, results)
+
+	received := requests()
+	require.Equal(t, 1, len(received))
+	input := received[0].body["batch_input"].([]interface{})
+	require.Equal(t, map[string]interface{}{"plaintext": ""}, input[0])
+}
+
+func Test_Transit_EncryptBatch_failed(t *testing.T) {
+	server, _ := transitServer(http.StatusBadRequest, `{"errors": ["encryption key not found"]}`)
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	_, err = client.Transit("").EncryptBatch("k", []TransitBatchItem{{Plaintext: []byte("one")}})
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "encryption key not found"))
+}
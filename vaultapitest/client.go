@@ -5,6 +5,7 @@ package vaultapitest
 
 import "github.com/stretchr/testify/mock"
 import "time"
+import "io"
 import "github.com/shoenig/vaultapi"
 
 // Client is an autogenerated mock type for the Client type
@@ -35,6 +36,71 @@ func (mockerySelf *Client) AccessorCapabilities(path string, accessor string) ([
 	return r0, r1
 }
 
+// AccessorCapabilitiesMulti provides a mock function with given fields: paths, accessor
+func (mockerySelf *Client) AccessorCapabilitiesMulti(paths []string, accessor string) (map[string][]string, error) {
+	ret := mockerySelf.Called(paths, accessor)
+
+	var r0 map[string][]string
+	if rf, ok := ret.Get(0).(func([]string, string) map[string][]string); ok {
+		r0 = rf(paths, accessor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, string) error); ok {
+		r1 = rf(paths, accessor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditHash provides a mock function with given fields: path, input
+func (mockerySelf *Client) AuditHash(path string, input string) (string, error) {
+	ret := mockerySelf.Called(path, input)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(path, input)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(path, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClusterStatus provides a mock function with given fields:
+func (mockerySelf *Client) ClusterStatus() (vaultapi.ClusterStatus, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.ClusterStatus
+	if rf, ok := ret.Get(0).(func() vaultapi.ClusterStatus); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.ClusterStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateToken provides a mock function with given fields: opts
 func (mockerySelf *Client) CreateToken(opts vaultapi.TokenOptions) (vaultapi.CreatedToken, error) {
 	ret := mockerySelf.Called(opts)
@@ -56,13 +122,13 @@ func (mockerySelf *Client) CreateToken(opts vaultapi.TokenOptions) (vaultapi.Cre
 	return r0, r1
 }
 
-// CreateTokenRole provides a mock function with given fields: data
-func (mockerySelf *Client) CreateTokenRole(data vaultapi.TokenRoleOptions) error {
-	ret := mockerySelf.Called(data)
+// CreateTokenRole provides a mock function with given fields: role
+func (mockerySelf *Client) CreateTokenRole(role vaultapi.TokenRole) error {
+	ret := mockerySelf.Called(role)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(vaultapi.TokenRoleOptions) error); ok {
-		r0 = rf(data)
+	if rf, ok := ret.Get(0).(func(vaultapi.TokenRole) error); ok {
+		r0 = rf(role)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,6 +136,22 @@ func (mockerySelf *Client) CreateTokenRole(data vaultapi.TokenRoleOptions) error
 	return r0
 }
 
+// Database provides a mock function with given fields: mount
+func (mockerySelf *Client) Database(mount string) vaultapi.Database {
+	ret := mockerySelf.Called(mount)
+
+	var r0 vaultapi.Database
+	if rf, ok := ret.Get(0).(func(string) vaultapi.Database); ok {
+		r0 = rf(mount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(vaultapi.Database)
+		}
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: path
 func (mockerySelf *Client) Delete(path string) error {
 	ret := mockerySelf.Called(path)
@@ -112,41 +194,118 @@ func (mockerySelf *Client) DeleteTokenRole(name string) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: path
-func (mockerySelf *Client) Get(path string) (string, error) {
+// DisableAudit provides a mock function with given fields: path
+func (mockerySelf *Client) DisableAudit(path string) error {
 	ret := mockerySelf.Called(path)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
 		r0 = rf(path)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(path)
+	return r0
+}
+
+// DisableAuth provides a mock function with given fields: path
+func (mockerySelf *Client) DisableAuth(path string) error {
+	ret := mockerySelf.Called(path)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(path)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// GetPolicy provides a mock function with given fields: name
-func (mockerySelf *Client) GetPolicy(name string) (string, error) {
-	ret := mockerySelf.Called(name)
+// DisableMount provides a mock function with given fields: path
+func (mockerySelf *Client) DisableMount(path string) error {
+	ret := mockerySelf.Called(path)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(name)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(path)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableAudit provides a mock function with given fields: path, auditType, options
+func (mockerySelf *Client) EnableAudit(path string, auditType string, options map[string]string) error {
+	ret := mockerySelf.Called(path, auditType, options)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, map[string]string) error); ok {
+		r0 = rf(path, auditType, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableAuth provides a mock function with given fields: path, authType, config
+func (mockerySelf *Client) EnableAuth(path string, authType string, config vaultapi.MountConfig) error {
+	ret := mockerySelf.Called(path, authType, config)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, vaultapi.MountConfig) error); ok {
+		r0 = rf(path, authType, config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableMount provides a mock function with given fields: path, mountType, config
+func (mockerySelf *Client) EnableMount(path string, mountType string, config vaultapi.MountConfig) error {
+	ret := mockerySelf.Called(path, mountType, config)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, vaultapi.MountConfig) error); ok {
+		r0 = rf(path, mountType, config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GenerateRootCancel provides a mock function with given fields:
+func (mockerySelf *Client) GenerateRootCancel() error {
+	ret := mockerySelf.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GenerateRootInit provides a mock function with given fields: otp
+func (mockerySelf *Client) GenerateRootInit(otp string) (vaultapi.GenerateRootStatus, error) {
+	ret := mockerySelf.Called(otp)
+
+	var r0 vaultapi.GenerateRootStatus
+	if rf, ok := ret.Get(0).(func(string) vaultapi.GenerateRootStatus); ok {
+		r0 = rf(otp)
+	} else {
+		r0 = ret.Get(0).(vaultapi.GenerateRootStatus)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(name)
+		r1 = rf(otp)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -154,15 +313,15 @@ func (mockerySelf *Client) GetPolicy(name string) (string, error) {
 	return r0, r1
 }
 
-// Health provides a mock function with given fields:
-func (mockerySelf *Client) Health() (vaultapi.Health, error) {
+// GenerateRootStatus provides a mock function with given fields:
+func (mockerySelf *Client) GenerateRootStatus() (vaultapi.GenerateRootStatus, error) {
 	ret := mockerySelf.Called()
 
-	var r0 vaultapi.Health
-	if rf, ok := ret.Get(0).(func() vaultapi.Health); ok {
+	var r0 vaultapi.GenerateRootStatus
+	if rf, ok := ret.Get(0).(func() vaultapi.GenerateRootStatus); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(vaultapi.Health)
+		r0 = ret.Get(0).(vaultapi.GenerateRootStatus)
 	}
 
 	var r1 error
@@ -175,22 +334,20 @@ func (mockerySelf *Client) Health() (vaultapi.Health, error) {
 	return r0, r1
 }
 
-// Keys provides a mock function with given fields: path
-func (mockerySelf *Client) Keys(path string) ([]string, error) {
-	ret := mockerySelf.Called(path)
+// GenerateRootUpdate provides a mock function with given fields: key, nonce
+func (mockerySelf *Client) GenerateRootUpdate(key string, nonce string) (vaultapi.GenerateRootStatus, error) {
+	ret := mockerySelf.Called(key, nonce)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(string) []string); ok {
-		r0 = rf(path)
+	var r0 vaultapi.GenerateRootStatus
+	if rf, ok := ret.Get(0).(func(string, string) vaultapi.GenerateRootStatus); ok {
+		r0 = rf(key, nonce)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		r0 = ret.Get(0).(vaultapi.GenerateRootStatus)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(path)
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(key, nonce)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -198,20 +355,20 @@ func (mockerySelf *Client) Keys(path string) ([]string, error) {
 	return r0, r1
 }
 
-// Leader provides a mock function with given fields:
-func (mockerySelf *Client) Leader() (vaultapi.Leader, error) {
-	ret := mockerySelf.Called()
+// Get provides a mock function with given fields: path
+func (mockerySelf *Client) Get(path string) (string, error) {
+	ret := mockerySelf.Called(path)
 
-	var r0 vaultapi.Leader
-	if rf, ok := ret.Get(0).(func() vaultapi.Leader); ok {
-		r0 = rf()
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(path)
 	} else {
-		r0 = ret.Get(0).(vaultapi.Leader)
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -219,22 +376,20 @@ func (mockerySelf *Client) Leader() (vaultapi.Leader, error) {
 	return r0, r1
 }
 
-// ListMounts provides a mock function with given fields:
-func (mockerySelf *Client) ListMounts() (vaultapi.Mounts, error) {
-	ret := mockerySelf.Called()
+// GetPolicy provides a mock function with given fields: name
+func (mockerySelf *Client) GetPolicy(name string) (string, error) {
+	ret := mockerySelf.Called(name)
 
-	var r0 vaultapi.Mounts
-	if rf, ok := ret.Get(0).(func() vaultapi.Mounts); ok {
-		r0 = rf()
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(name)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(vaultapi.Mounts)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -242,22 +397,20 @@ func (mockerySelf *Client) ListMounts() (vaultapi.Mounts, error) {
 	return r0, r1
 }
 
-// ListPolicies provides a mock function with given fields:
-func (mockerySelf *Client) ListPolicies() ([]string, error) {
-	ret := mockerySelf.Called()
+// GetPolicyParsed provides a mock function with given fields: name
+func (mockerySelf *Client) GetPolicyParsed(name string) (vaultapi.Policy, error) {
+	ret := mockerySelf.Called(name)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func() []string); ok {
-		r0 = rf()
+	var r0 vaultapi.Policy
+	if rf, ok := ret.Get(0).(func(string) vaultapi.Policy); ok {
+		r0 = rf(name)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		r0 = ret.Get(0).(vaultapi.Policy)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -265,17 +418,15 @@ func (mockerySelf *Client) ListPolicies() ([]string, error) {
 	return r0, r1
 }
 
-// ListTokenRoles provides a mock function with given fields:
-func (mockerySelf *Client) ListTokenRoles() ([]string, error) {
+// Health provides a mock function with given fields:
+func (mockerySelf *Client) Health() (vaultapi.Health, error) {
 	ret := mockerySelf.Called()
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func() []string); ok {
+	var r0 vaultapi.Health
+	if rf, ok := ret.Get(0).(func() vaultapi.Health); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		r0 = ret.Get(0).(vaultapi.Health)
 	}
 
 	var r1 error
@@ -288,20 +439,20 @@ func (mockerySelf *Client) ListTokenRoles() ([]string, error) {
 	return r0, r1
 }
 
-// LookupLease provides a mock function with given fields: id
-func (mockerySelf *Client) LookupLease(id string) (vaultapi.Lease, error) {
-	ret := mockerySelf.Called(id)
+// Init provides a mock function with given fields: opts
+func (mockerySelf *Client) Init(opts vaultapi.InitOptions) (vaultapi.InitResponse, error) {
+	ret := mockerySelf.Called(opts)
 
-	var r0 vaultapi.Lease
-	if rf, ok := ret.Get(0).(func(string) vaultapi.Lease); ok {
-		r0 = rf(id)
+	var r0 vaultapi.InitResponse
+	if rf, ok := ret.Get(0).(func(vaultapi.InitOptions) vaultapi.InitResponse); ok {
+		r0 = rf(opts)
 	} else {
-		r0 = ret.Get(0).(vaultapi.Lease)
+		r0 = ret.Get(0).(vaultapi.InitResponse)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(vaultapi.InitOptions) error); ok {
+		r1 = rf(opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -309,20 +460,20 @@ func (mockerySelf *Client) LookupLease(id string) (vaultapi.Lease, error) {
 	return r0, r1
 }
 
-// LookupSelfToken provides a mock function with given fields:
-func (mockerySelf *Client) LookupSelfToken() (vaultapi.LookedUpToken, error) {
-	ret := mockerySelf.Called()
+// InitServer provides a mock function with given fields: address, opts
+func (mockerySelf *Client) InitServer(address string, opts vaultapi.InitOptions) (vaultapi.InitResponse, error) {
+	ret := mockerySelf.Called(address, opts)
 
-	var r0 vaultapi.LookedUpToken
-	if rf, ok := ret.Get(0).(func() vaultapi.LookedUpToken); ok {
-		r0 = rf()
+	var r0 vaultapi.InitResponse
+	if rf, ok := ret.Get(0).(func(string, vaultapi.InitOptions) vaultapi.InitResponse); ok {
+		r0 = rf(address, opts)
 	} else {
-		r0 = ret.Get(0).(vaultapi.LookedUpToken)
+		r0 = ret.Get(0).(vaultapi.InitResponse)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string, vaultapi.InitOptions) error); ok {
+		r1 = rf(address, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -330,20 +481,20 @@ func (mockerySelf *Client) LookupSelfToken() (vaultapi.LookedUpToken, error) {
 	return r0, r1
 }
 
-// LookupToken provides a mock function with given fields: id
-func (mockerySelf *Client) LookupToken(id string) (vaultapi.LookedUpToken, error) {
-	ret := mockerySelf.Called(id)
+// InitStatus provides a mock function with given fields:
+func (mockerySelf *Client) InitStatus() (bool, error) {
+	ret := mockerySelf.Called()
 
-	var r0 vaultapi.LookedUpToken
-	if rf, ok := ret.Get(0).(func(string) vaultapi.LookedUpToken); ok {
-		r0 = rf(id)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(vaultapi.LookedUpToken)
+		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -351,20 +502,20 @@ func (mockerySelf *Client) LookupToken(id string) (vaultapi.LookedUpToken, error
 	return r0, r1
 }
 
-// LookupTokenRole provides a mock function with given fields: name
-func (mockerySelf *Client) LookupTokenRole(name string) (vaultapi.LookedUpTokenRole, error) {
-	ret := mockerySelf.Called(name)
+// InitStatusServer provides a mock function with given fields: address
+func (mockerySelf *Client) InitStatusServer(address string) (bool, error) {
+	ret := mockerySelf.Called(address)
 
-	var r0 vaultapi.LookedUpTokenRole
-	if rf, ok := ret.Get(0).(func(string) vaultapi.LookedUpTokenRole); ok {
-		r0 = rf(name)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(address)
 	} else {
-		r0 = ret.Get(0).(vaultapi.LookedUpTokenRole)
+		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(name)
+		r1 = rf(address)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -372,34 +523,783 @@ func (mockerySelf *Client) LookupTokenRole(name string) (vaultapi.LookedUpTokenR
 	return r0, r1
 }
 
+// Keys provides a mock function with given fields: path
+func (mockerySelf *Client) Keys(path string) ([]string, error) {
+	ret := mockerySelf.Called(path)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Leader provides a mock function with given fields:
+func (mockerySelf *Client) Leader() (vaultapi.Leader, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.Leader
+	if rf, ok := ret.Get(0).(func() vaultapi.Leader); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.Leader)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LintPolicies provides a mock function with given fields:
+func (mockerySelf *Client) LintPolicies() ([]vaultapi.PolicyFinding, error) {
+	ret := mockerySelf.Called()
+
+	var r0 []vaultapi.PolicyFinding
+	if rf, ok := ret.Get(0).(func() []vaultapi.PolicyFinding); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]vaultapi.PolicyFinding)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAudit provides a mock function with given fields:
+func (mockerySelf *Client) ListAudit() (vaultapi.AuditDevices, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.AuditDevices
+	if rf, ok := ret.Get(0).(func() vaultapi.AuditDevices); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(vaultapi.AuditDevices)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuthMounts provides a mock function with given fields:
+func (mockerySelf *Client) ListAuthMounts() (vaultapi.AuthMounts, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.AuthMounts
+	if rf, ok := ret.Get(0).(func() vaultapi.AuthMounts); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(vaultapi.AuthMounts)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLeases provides a mock function with given fields: prefix
+func (mockerySelf *Client) ListLeases(prefix string) ([]string, error) {
+	ret := mockerySelf.Called(prefix)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMounts provides a mock function with given fields:
+func (mockerySelf *Client) ListMounts() (vaultapi.Mounts, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.Mounts
+	if rf, ok := ret.Get(0).(func() vaultapi.Mounts); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(vaultapi.Mounts)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPolicies provides a mock function with given fields:
+func (mockerySelf *Client) ListPolicies() ([]string, error) {
+	ret := mockerySelf.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTokenRoles provides a mock function with given fields:
+func (mockerySelf *Client) ListTokenRoles() ([]string, error) {
+	ret := mockerySelf.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logical provides a mock function with given fields: opts
+func (mockerySelf *Client) Logical(opts ...vaultapi.RequestOption) vaultapi.Logical {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := mockerySelf.Called(_ca...)
+
+	var r0 vaultapi.Logical
+	if rf, ok := ret.Get(0).(func(...vaultapi.RequestOption) vaultapi.Logical); ok {
+		r0 = rf(opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(vaultapi.Logical)
+		}
+	}
+
+	return r0
+}
+
+// LookupLease provides a mock function with given fields: id
+func (mockerySelf *Client) LookupLease(id string) (vaultapi.Lease, error) {
+	ret := mockerySelf.Called(id)
+
+	var r0 vaultapi.Lease
+	if rf, ok := ret.Get(0).(func(string) vaultapi.Lease); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(vaultapi.Lease)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LookupSelfToken provides a mock function with given fields:
+func (mockerySelf *Client) LookupSelfToken() (vaultapi.LookedUpToken, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.LookedUpToken
+	if rf, ok := ret.Get(0).(func() vaultapi.LookedUpToken); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.LookedUpToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LookupToken provides a mock function with given fields: id
+func (mockerySelf *Client) LookupToken(id string) (vaultapi.LookedUpToken, error) {
+	ret := mockerySelf.Called(id)
+
+	var r0 vaultapi.LookedUpToken
+	if rf, ok := ret.Get(0).(func(string) vaultapi.LookedUpToken); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(vaultapi.LookedUpToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LookupTokenRole provides a mock function with given fields: name
+func (mockerySelf *Client) LookupTokenRole(name string) (vaultapi.TokenRole, error) {
+	ret := mockerySelf.Called(name)
+
+	var r0 vaultapi.TokenRole
+	if rf, ok := ret.Get(0).(func(string) vaultapi.TokenRole); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(vaultapi.TokenRole)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LookupWrapping provides a mock function with given fields: token
+func (mockerySelf *Client) LookupWrapping(token string) (vaultapi.WrappingLookup, error) {
+	ret := mockerySelf.Called(token)
+
+	var r0 vaultapi.WrappingLookup
+	if rf, ok := ret.Get(0).(func(string) vaultapi.WrappingLookup); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(vaultapi.WrappingLookup)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PKI provides a mock function with given fields: mount
+func (mockerySelf *Client) PKI(mount string) vaultapi.PKI {
+	ret := mockerySelf.Called(mount)
+
+	var r0 vaultapi.PKI
+	if rf, ok := ret.Get(0).(func(string) vaultapi.PKI); ok {
+		r0 = rf(mount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(vaultapi.PKI)
+		}
+	}
+
+	return r0
+}
+
 // Put provides a mock function with given fields: path, value
 func (mockerySelf *Client) Put(path string, value string) error {
 	ret := mockerySelf.Called(path, value)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(path, value)
+		r0 = rf(path, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RaftAutopilotConfig provides a mock function with given fields:
+func (mockerySelf *Client) RaftAutopilotConfig() (vaultapi.AutopilotConfig, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.AutopilotConfig
+	if rf, ok := ret.Get(0).(func() vaultapi.AutopilotConfig); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.AutopilotConfig)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RaftAutopilotState provides a mock function with given fields:
+func (mockerySelf *Client) RaftAutopilotState() (vaultapi.AutopilotState, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.AutopilotState
+	if rf, ok := ret.Get(0).(func() vaultapi.AutopilotState); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.AutopilotState)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RaftConfiguration provides a mock function with given fields:
+func (mockerySelf *Client) RaftConfiguration() (vaultapi.RaftConfiguration, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.RaftConfiguration
+	if rf, ok := ret.Get(0).(func() vaultapi.RaftConfiguration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.RaftConfiguration)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RaftJoin provides a mock function with given fields: opts
+func (mockerySelf *Client) RaftJoin(opts vaultapi.RaftJoinOptions) (bool, error) {
+	ret := mockerySelf.Called(opts)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(vaultapi.RaftJoinOptions) bool); ok {
+		r0 = rf(opts)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(vaultapi.RaftJoinOptions) error); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RaftRemovePeer provides a mock function with given fields: id
+func (mockerySelf *Client) RaftRemovePeer(id string) error {
+	ret := mockerySelf.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadMountTune provides a mock function with given fields: path
+func (mockerySelf *Client) ReadMountTune(path string) (vaultapi.MountConfig, error) {
+	ret := mockerySelf.Called(path)
+
+	var r0 vaultapi.MountConfig
+	if rf, ok := ret.Get(0).(func(string) vaultapi.MountConfig); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Get(0).(vaultapi.MountConfig)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RekeyCancel provides a mock function with given fields:
+func (mockerySelf *Client) RekeyCancel() error {
+	ret := mockerySelf.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RekeyInit provides a mock function with given fields: opts
+func (mockerySelf *Client) RekeyInit(opts vaultapi.RekeyOptions) (vaultapi.RekeyStatus, error) {
+	ret := mockerySelf.Called(opts)
+
+	var r0 vaultapi.RekeyStatus
+	if rf, ok := ret.Get(0).(func(vaultapi.RekeyOptions) vaultapi.RekeyStatus); ok {
+		r0 = rf(opts)
+	} else {
+		r0 = ret.Get(0).(vaultapi.RekeyStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(vaultapi.RekeyOptions) error); ok {
+		r1 = rf(opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RekeyStatus provides a mock function with given fields:
+func (mockerySelf *Client) RekeyStatus() (vaultapi.RekeyStatus, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.RekeyStatus
+	if rf, ok := ret.Get(0).(func() vaultapi.RekeyStatus); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.RekeyStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RekeyUpdate provides a mock function with given fields: key, nonce
+func (mockerySelf *Client) RekeyUpdate(key string, nonce string) (vaultapi.RekeyStatus, error) {
+	ret := mockerySelf.Called(key, nonce)
+
+	var r0 vaultapi.RekeyStatus
+	if rf, ok := ret.Get(0).(func(string, string) vaultapi.RekeyStatus); ok {
+		r0 = rf(key, nonce)
+	} else {
+		r0 = ret.Get(0).(vaultapi.RekeyStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(key, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remount provides a mock function with given fields: from, to
+func (mockerySelf *Client) Remount(from string, to string) error {
+	ret := mockerySelf.Called(from, to)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenewLease provides a mock function with given fields: id, increment
+func (mockerySelf *Client) RenewLease(id string, increment time.Duration) (vaultapi.RenewedLease, error) {
+	ret := mockerySelf.Called(id, increment)
+
+	var r0 vaultapi.RenewedLease
+	if rf, ok := ret.Get(0).(func(string, time.Duration) vaultapi.RenewedLease); ok {
+		r0 = rf(id, increment)
+	} else {
+		r0 = ret.Get(0).(vaultapi.RenewedLease)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(id, increment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenewSelfToken provides a mock function with given fields: increment
+func (mockerySelf *Client) RenewSelfToken(increment time.Duration) (vaultapi.RenewedToken, error) {
+	ret := mockerySelf.Called(increment)
+
+	var r0 vaultapi.RenewedToken
+	if rf, ok := ret.Get(0).(func(time.Duration) vaultapi.RenewedToken); ok {
+		r0 = rf(increment)
+	} else {
+		r0 = ret.Get(0).(vaultapi.RenewedToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
+		r1 = rf(increment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenewToken provides a mock function with given fields: id, increment
+func (mockerySelf *Client) RenewToken(id string, increment time.Duration) (vaultapi.RenewedToken, error) {
+	ret := mockerySelf.Called(id, increment)
+
+	var r0 vaultapi.RenewedToken
+	if rf, ok := ret.Get(0).(func(string, time.Duration) vaultapi.RenewedToken); ok {
+		r0 = rf(id, increment)
+	} else {
+		r0 = ret.Get(0).(vaultapi.RenewedToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(id, increment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequireCapabilities provides a mock function with given fields: required
+func (mockerySelf *Client) RequireCapabilities(required map[string][]string) error {
+	ret := mockerySelf.Called(required)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(map[string][]string) error); ok {
+		r0 = rf(required)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeLease provides a mock function with given fields: id
+func (mockerySelf *Client) RevokeLease(id string) error {
+	ret := mockerySelf.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeLeaseForce provides a mock function with given fields: prefix
+func (mockerySelf *Client) RevokeLeaseForce(prefix string) error {
+	ret := mockerySelf.Called(prefix)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(prefix)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeLeasePrefix provides a mock function with given fields: prefix
+func (mockerySelf *Client) RevokeLeasePrefix(prefix string) error {
+	ret := mockerySelf.Called(prefix)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(prefix)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RewrapToken provides a mock function with given fields: token
+func (mockerySelf *Client) RewrapToken(token string) (vaultapi.WrapInfo, error) {
+	ret := mockerySelf.Called(token)
+
+	var r0 vaultapi.WrapInfo
+	if rf, ok := ret.Get(0).(func(string) vaultapi.WrapInfo); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(vaultapi.WrapInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Seal provides a mock function with given fields:
+func (mockerySelf *Client) Seal() error {
+	ret := mockerySelf.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SealServer provides a mock function with given fields: address
+func (mockerySelf *Client) SealServer(address string) error {
+	ret := mockerySelf.Called(address)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(address)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SealStatus provides a mock function with given fields:
+func (mockerySelf *Client) SealStatus() (vaultapi.SealStatus, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.SealStatus
+	if rf, ok := ret.Get(0).(func() vaultapi.SealStatus); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.SealStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// RenewSelfToken provides a mock function with given fields: increment
-func (mockerySelf *Client) RenewSelfToken(increment time.Duration) (vaultapi.RenewedToken, error) {
-	ret := mockerySelf.Called(increment)
+// SelfCapabilities provides a mock function with given fields: path
+func (mockerySelf *Client) SelfCapabilities(path string) ([]string, error) {
+	ret := mockerySelf.Called(path)
 
-	var r0 vaultapi.RenewedToken
-	if rf, ok := ret.Get(0).(func(time.Duration) vaultapi.RenewedToken); ok {
-		r0 = rf(increment)
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(path)
 	} else {
-		r0 = ret.Get(0).(vaultapi.RenewedToken)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
-		r1 = rf(increment)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -407,20 +1307,22 @@ func (mockerySelf *Client) RenewSelfToken(increment time.Duration) (vaultapi.Ren
 	return r0, r1
 }
 
-// RenewToken provides a mock function with given fields: id, increment
-func (mockerySelf *Client) RenewToken(id string, increment time.Duration) (vaultapi.RenewedToken, error) {
-	ret := mockerySelf.Called(id, increment)
+// SelfCapabilitiesMulti provides a mock function with given fields: paths
+func (mockerySelf *Client) SelfCapabilitiesMulti(paths []string) (map[string][]string, error) {
+	ret := mockerySelf.Called(paths)
 
-	var r0 vaultapi.RenewedToken
-	if rf, ok := ret.Get(0).(func(string, time.Duration) vaultapi.RenewedToken); ok {
-		r0 = rf(id, increment)
+	var r0 map[string][]string
+	if rf, ok := ret.Get(0).(func([]string) map[string][]string); ok {
+		r0 = rf(paths)
 	} else {
-		r0 = ret.Get(0).(vaultapi.RenewedToken)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]string)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
-		r1 = rf(id, increment)
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(paths)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -428,20 +1330,20 @@ func (mockerySelf *Client) RenewToken(id string, increment time.Duration) (vault
 	return r0, r1
 }
 
-// SealStatus provides a mock function with given fields:
-func (mockerySelf *Client) SealStatus() (vaultapi.SealStatus, error) {
-	ret := mockerySelf.Called()
+// ServerHealth provides a mock function with given fields: address, opts
+func (mockerySelf *Client) ServerHealth(address string, opts vaultapi.HealthOptions) (vaultapi.Health, error) {
+	ret := mockerySelf.Called(address, opts)
 
-	var r0 vaultapi.SealStatus
-	if rf, ok := ret.Get(0).(func() vaultapi.SealStatus); ok {
-		r0 = rf()
+	var r0 vaultapi.Health
+	if rf, ok := ret.Get(0).(func(string, vaultapi.HealthOptions) vaultapi.Health); ok {
+		r0 = rf(address, opts)
 	} else {
-		r0 = ret.Get(0).(vaultapi.SealStatus)
+		r0 = ret.Get(0).(vaultapi.Health)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string, vaultapi.HealthOptions) error); ok {
+		r1 = rf(address, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -449,22 +1351,22 @@ func (mockerySelf *Client) SealStatus() (vaultapi.SealStatus, error) {
 	return r0, r1
 }
 
-// SelfCapabilities provides a mock function with given fields: path
-func (mockerySelf *Client) SelfCapabilities(path string) ([]string, error) {
-	ret := mockerySelf.Called(path)
+// ServersHealth provides a mock function with given fields: opts
+func (mockerySelf *Client) ServersHealth(opts vaultapi.HealthOptions) (map[string]vaultapi.Health, error) {
+	ret := mockerySelf.Called(opts)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(string) []string); ok {
-		r0 = rf(path)
+	var r0 map[string]vaultapi.Health
+	if rf, ok := ret.Get(0).(func(vaultapi.HealthOptions) map[string]vaultapi.Health); ok {
+		r0 = rf(opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(map[string]vaultapi.Health)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(path)
+	if rf, ok := ret.Get(1).(func(vaultapi.HealthOptions) error); ok {
+		r1 = rf(opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -486,6 +1388,91 @@ func (mockerySelf *Client) SetPolicy(name string, content string) error {
 	return r0
 }
 
+// SetPolicyTyped provides a mock function with given fields: name, policy
+func (mockerySelf *Client) SetPolicyTyped(name string, policy vaultapi.Policy) error {
+	ret := mockerySelf.Called(name, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, vaultapi.Policy) error); ok {
+		r0 = rf(name, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRaftAutopilotConfig provides a mock function with given fields: config
+func (mockerySelf *Client) SetRaftAutopilotConfig(config vaultapi.AutopilotConfig) error {
+	ret := mockerySelf.Called(config)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(vaultapi.AutopilotConfig) error); ok {
+		r0 = rf(config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SnapshotRestore provides a mock function with given fields: r, force, opts
+func (mockerySelf *Client) SnapshotRestore(r io.Reader, force bool, opts ...vaultapi.SnapshotOption) (vaultapi.SnapshotInfo, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, r)
+	_ca = append(_ca, force)
+	_ca = append(_ca, _va...)
+	ret := mockerySelf.Called(_ca...)
+
+	var r0 vaultapi.SnapshotInfo
+	if rf, ok := ret.Get(0).(func(io.Reader, bool, ...vaultapi.SnapshotOption) vaultapi.SnapshotInfo); ok {
+		r0 = rf(r, force, opts...)
+	} else {
+		r0 = ret.Get(0).(vaultapi.SnapshotInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(io.Reader, bool, ...vaultapi.SnapshotOption) error); ok {
+		r1 = rf(r, force, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SnapshotSave provides a mock function with given fields: w, opts
+func (mockerySelf *Client) SnapshotSave(w io.Writer, opts ...vaultapi.SnapshotOption) (vaultapi.SnapshotInfo, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, w)
+	_ca = append(_ca, _va...)
+	ret := mockerySelf.Called(_ca...)
+
+	var r0 vaultapi.SnapshotInfo
+	if rf, ok := ret.Get(0).(func(io.Writer, ...vaultapi.SnapshotOption) vaultapi.SnapshotInfo); ok {
+		r0 = rf(w, opts...)
+	} else {
+		r0 = ret.Get(0).(vaultapi.SnapshotInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(io.Writer, ...vaultapi.SnapshotOption) error); ok {
+		r1 = rf(w, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StepDown provides a mock function with given fields:
 func (mockerySelf *Client) StepDown() error {
 	ret := mockerySelf.Called()
@@ -500,6 +1487,27 @@ func (mockerySelf *Client) StepDown() error {
 	return r0
 }
 
+// SyncPolicies provides a mock function with given fields: desired, opts
+func (mockerySelf *Client) SyncPolicies(desired map[string]string, opts vaultapi.PolicySyncOptions) (vaultapi.PolicyPlan, error) {
+	ret := mockerySelf.Called(desired, opts)
+
+	var r0 vaultapi.PolicyPlan
+	if rf, ok := ret.Get(0).(func(map[string]string, vaultapi.PolicySyncOptions) vaultapi.PolicyPlan); ok {
+		r0 = rf(desired, opts)
+	} else {
+		r0 = ret.Get(0).(vaultapi.PolicyPlan)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(map[string]string, vaultapi.PolicySyncOptions) error); ok {
+		r1 = rf(desired, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TokenCapabilities provides a mock function with given fields: path, token
 func (mockerySelf *Client) TokenCapabilities(path string, token string) ([]string, error) {
 	ret := mockerySelf.Called(path, token)
@@ -522,3 +1530,212 @@ func (mockerySelf *Client) TokenCapabilities(path string, token string) ([]strin
 
 	return r0, r1
 }
+
+// TokenCapabilitiesMulti provides a mock function with given fields: paths, token
+func (mockerySelf *Client) TokenCapabilitiesMulti(paths []string, token string) (map[string][]string, error) {
+	ret := mockerySelf.Called(paths, token)
+
+	var r0 map[string][]string
+	if rf, ok := ret.Get(0).(func([]string, string) map[string][]string); ok {
+		r0 = rf(paths, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, string) error); ok {
+		r1 = rf(paths, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transit provides a mock function with given fields: mount
+func (mockerySelf *Client) Transit(mount string) vaultapi.Transit {
+	ret := mockerySelf.Called(mount)
+
+	var r0 vaultapi.Transit
+	if rf, ok := ret.Get(0).(func(string) vaultapi.Transit); ok {
+		r0 = rf(mount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(vaultapi.Transit)
+		}
+	}
+
+	return r0
+}
+
+// TuneAuth provides a mock function with given fields: path, config
+func (mockerySelf *Client) TuneAuth(path string, config vaultapi.MountConfig) error {
+	ret := mockerySelf.Called(path, config)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, vaultapi.MountConfig) error); ok {
+		r0 = rf(path, config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TuneMount provides a mock function with given fields: path, config
+func (mockerySelf *Client) TuneMount(path string, config vaultapi.MountConfig) error {
+	ret := mockerySelf.Called(path, config)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, vaultapi.MountConfig) error); ok {
+		r0 = rf(path, config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unseal provides a mock function with given fields: key
+func (mockerySelf *Client) Unseal(key string) (vaultapi.SealStatus, error) {
+	ret := mockerySelf.Called(key)
+
+	var r0 vaultapi.SealStatus
+	if rf, ok := ret.Get(0).(func(string) vaultapi.SealStatus); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(vaultapi.SealStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnsealReset provides a mock function with given fields:
+func (mockerySelf *Client) UnsealReset() (vaultapi.SealStatus, error) {
+	ret := mockerySelf.Called()
+
+	var r0 vaultapi.SealStatus
+	if rf, ok := ret.Get(0).(func() vaultapi.SealStatus); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(vaultapi.SealStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnsealResetServer provides a mock function with given fields: address
+func (mockerySelf *Client) UnsealResetServer(address string) (vaultapi.SealStatus, error) {
+	ret := mockerySelf.Called(address)
+
+	var r0 vaultapi.SealStatus
+	if rf, ok := ret.Get(0).(func(string) vaultapi.SealStatus); ok {
+		r0 = rf(address)
+	} else {
+		r0 = ret.Get(0).(vaultapi.SealStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnsealServer provides a mock function with given fields: address, key
+func (mockerySelf *Client) UnsealServer(address string, key string) (vaultapi.SealStatus, error) {
+	ret := mockerySelf.Called(address, key)
+
+	var r0 vaultapi.SealStatus
+	if rf, ok := ret.Get(0).(func(string, string) vaultapi.SealStatus); ok {
+		r0 = rf(address, key)
+	} else {
+		r0 = ret.Get(0).(vaultapi.SealStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(address, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unwrap provides a mock function with given fields: token
+func (mockerySelf *Client) Unwrap(token string) (*vaultapi.Secret, error) {
+	ret := mockerySelf.Called(token)
+
+	var r0 *vaultapi.Secret
+	if rf, ok := ret.Get(0).(func(string) *vaultapi.Secret); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*vaultapi.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTokenRole provides a mock function with given fields: name, update
+func (mockerySelf *Client) UpdateTokenRole(name string, update func(*vaultapi.TokenRole)) error {
+	ret := mockerySelf.Called(name, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, func(*vaultapi.TokenRole)) error); ok {
+		r0 = rf(name, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Wrap provides a mock function with given fields: data, ttl
+func (mockerySelf *Client) Wrap(data map[string]interface{}, ttl time.Duration) (vaultapi.WrapInfo, error) {
+	ret := mockerySelf.Called(data, ttl)
+
+	var r0 vaultapi.WrapInfo
+	if rf, ok := ret.Get(0).(func(map[string]interface{}, time.Duration) vaultapi.WrapInfo); ok {
+		r0 = rf(data, ttl)
+	} else {
+		r0 = ret.Get(0).(vaultapi.WrapInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(map[string]interface{}, time.Duration) error); ok {
+		r1 = rf(data, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
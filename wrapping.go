@@ -0,0 +1,113 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithWrapTTL is a RequestOption which asks vault to wrap the response
+// in a single-use token that is valid for ttl. The wrapping token is then
+// returned in the WrapInfo of the resulting Secret, instead of the data.
+// The ttl is rounded up to whole seconds, and must be positive, otherwise
+// every request fails rather than returning the response unwrapped.
+//
+// More information about response wrapping can be found here:
+// https://www.vaultproject.io/docs/concepts/response-wrapping.html
+func WithWrapTTL(ttl time.Duration) RequestOption {
+	return func(ro *requestOptions) {
+		if ttl <= 0 {
+			ro.err = errors.Errorf("wrap ttl must be positive, got %v", ttl)
+			return
+		}
+		ro.headers.Set(headerWrapTTL, ttlString(ttl))
+	}
+}
+
+// A WrappingLookup represents information returned from vault
+// after looking up a wrapping token.
+type WrappingLookup struct {
+	CreationPath string    `json:"creation_path"`
+	CreationTime time.Time `json:"creation_time"`
+	CreationTTL  int       `json:"creation_ttl"`
+}
+
+type wrappingLookupWrapper struct {
+	Data WrappingLookup `json:"data"`
+}
+
+type wrappingToken struct {
+	Token string `json:"token"`
+}
+
+func (c *client) Wrap(data map[string]interface{}, ttl time.Duration) (WrapInfo, error) {
+	if ttl <= 0 {
+		return WrapInfo{}, errors.Errorf("wrap ttl must be positive, got %v", ttl)
+	}
+
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return WrapInfo{}, errors.Wrap(err, "failed to create json for wrapping data")
+	}
+
+	var secret Secret
+	if err := c.with(WithWrapTTL(ttl)).post("/v1/sys/wrapping/wrap", string(bs), &secret); err != nil {
+		return WrapInfo{}, errors.Wrap(err, "failed to wrap data")
+	}
+
+	if secret.WrapInfo == nil {
+		return WrapInfo{}, errors.New("wrap returned no wrapping token")
+	}
+
+	return *secret.WrapInfo, nil
+}
+
+func (c *client) Unwrap(token string) (*Secret, error) {
+	bs, err := json.Marshal(wrappingToken{Token: token})
+	if err != nil {
+		return nil, err
+	}
+
+	var secret Secret
+	if err := c.post("/v1/sys/wrapping/unwrap", string(bs), &secret); err != nil {
+		// do not provide token anywhere
+		return nil, errors.Wrap(err, "failed to unwrap token")
+	}
+
+	return &secret, nil
+}
+
+func (c *client) RewrapToken(token string) (WrapInfo, error) {
+	bs, err := json.Marshal(wrappingToken{Token: token})
+	if err != nil {
+		return WrapInfo{}, err
+	}
+
+	var secret Secret
+	if err := c.post("/v1/sys/wrapping/rewrap", string(bs), &secret); err != nil {
+		// do not provide token anywhere
+		return WrapInfo{}, errors.Wrap(err, "failed to rewrap token")
+	}
+
+	if secret.WrapInfo == nil {
+		return WrapInfo{}, errors.New("rewrap returned no wrapping token")
+	}
+
+	return *secret.WrapInfo, nil
+}
+
+func (c *client) LookupWrapping(token string) (WrappingLookup, error) {
+	bs, err := json.Marshal(wrappingToken{Token: token})
+	if err != nil {
+		return WrappingLookup{}, err
+	}
+
+	var wrapper wrappingLookupWrapper
+	if err := c.post("/v1/sys/wrapping/lookup", string(bs), &wrapper); err != nil {
+		// do not provide token anywhere
+		return WrappingLookup{}, errors.Wrap(err, "failed to lookup wrapping token")
+	}
+
+	return wrapper.Data, nil
+}
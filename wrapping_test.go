@@ -0,0 +1,98 @@
+package vaultapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Wrapping(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	info, err := client.Wrap(map[string]interface{}{
+		"password": "hunter2",
+	}, 5*time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, info.Token)
+	require.Equal(t, 300, info.TTL)
+	require.Equal(t, "sys/wrapping/wrap", info.CreationPath)
+
+	lookup, err := client.LookupWrapping(info.Token)
+	require.NoError(t, err)
+	require.Equal(t, "sys/wrapping/wrap", lookup.CreationPath)
+	require.Equal(t, 300, lookup.CreationTTL)
+
+	rewrapped, err := client.RewrapToken(info.Token)
+	require.NoError(t, err)
+	require.NotEqual(t, info.Token, rewrapped.Token)
+
+	// the original token is no longer valid after rewrapping
+	_, err = client.Unwrap(info.Token)
+	require.Error(t, err)
+
+	secret, err := client.Unwrap(rewrapped.Token)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", secret.Data["password"])
+
+	// wrapping tokens are single use
+	_, err = client.Unwrap(rewrapped.Token)
+	require.Error(t, err)
+}
+
+func Test_Wrapping_Logical(t *testing.T) {
+	client := getClient(t, rootTokener)
+
+	err := client.Put("/wrapped", "secret-value")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, client.Delete("/wrapped"))
+	}()
+
+	wrapped, err := client.Logical(WithWrapTTL(1 * time.Minute)).Read("secret/wrapped")
+	require.NoError(t, err)
+	require.NotNil(t, wrapped.WrapInfo)
+	require.Nil(t, wrapped.Data)
+	require.Equal(t, "secret/wrapped", wrapped.WrapInfo.CreationPath)
+
+	secret, err := client.Unwrap(wrapped.WrapInfo.Token)
+	require.NoError(t, err)
+	require.Equal(t, "secret-value", secret.Data["value"])
+}
+
+func Test_WithWrapTTL(t *testing.T) {
+	var lock sync.Mutex
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		headers = append(headers, r.Header.Get(headerWrapTTL))
+		lock.Unlock()
+		_, _ = w.Write([]byte(`{"wrap_info": {"token": "s.abc", "ttl": 1}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(ClientOptions{Servers: []string{server.URL}}, NewStaticToken("abc123"))
+	require.NoError(t, err)
+
+	// a secret must never be returned unwrapped because of a bad ttl
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		_, err := client.Logical(WithWrapTTL(ttl)).Read("secret/foo")
+		require.Error(t, err, "ttl: %v", ttl)
+		_, err = client.Wrap(map[string]interface{}{"a": "b"}, ttl)
+		require.Error(t, err, "ttl: %v", ttl)
+	}
+
+	secret, err := client.Logical(WithWrapTTL(500 * time.Millisecond)).Read("secret/foo")
+	require.NoError(t, err)
+	require.Equal(t, "s.abc", secret.WrapInfo.Token)
+
+	_, err = client.Logical(WithWrapTTL(90 * time.Second)).Read("secret/foo")
+	require.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, []string{"1s", "90s"}, headers)
+}